@@ -0,0 +1,289 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"dsb-excel-generator/pkg/config"
+	"dsb-excel-generator/pkg/excel"
+	"dsb-excel-generator/pkg/models"
+	"dsb-excel-generator/pkg/pdf"
+)
+
+var commands = []command{
+	{
+		name:    "generate-data",
+		summary: "Generate the Excel workbook with mock employee data",
+		flags: func(fs *flag.FlagSet, cfg *config.Config) {
+			fs.IntVar(&cfg.Data.Rows, "rows", cfg.Data.Rows, "number of employee rows")
+			fs.Int64Var(&cfg.Data.Seed, "seed", cfg.Data.Seed, "random seed for reproducible data (0 uses the current time)")
+			fs.BoolVar(&cfg.Data.AllocateBudgets, "allocate-budgets", cfg.Data.AllocateBudgets, "keep individual adjustments within the department budgets")
+			fs.BoolVar(&cfg.Data.Formulas, "formulas", cfg.Data.Formulas, "write derived salary columns as formulas over PercentageIncrease and the other inputs")
+			fs.Float64Var(&cfg.Data.HighlightIncrease, "highlight-increase", cfg.Data.HighlightIncrease, "highlight individual increases above this percentage for review")
+			fs.BoolVar(&cfg.Data.Protect, "protect", cfg.Data.Protect, "lock the workbook except PercentageIncrease, AdditionalNotes and the approval columns")
+		},
+		run: func(cfg config.Config) error {
+			return excel.Generate(cfg.Workbook, cfg.ExcelOptions())
+		},
+	},
+	{
+		name:    "render",
+		summary: "Render individual PDF letters from the workbook",
+		flags: func(fs *flag.FlagSet, cfg *config.Config) {
+			r := &cfg.Render
+			fs.StringVar(&r.OutputDir, "output-dir", r.OutputDir, "directory for the PDF letters")
+			fs.IntVar(&r.Limit, "limit", r.Limit, "number of letters to generate (0 for all rows)")
+			fs.IntVar(&r.Sample, "sample", r.Sample, "generate a stratified QA sample of N letters instead of the first rows")
+			fs.StringVar(&r.Filter, "filter", r.Filter, `select rows with an expression over header names, e.g. 'Department == "IT" && PercentageIncrease > 3'`)
+			fs.StringVar(&r.Include, "include", r.Include, "`file` listing employee numbers or CPR numbers to include")
+			fs.StringVar(&r.Exclude, "exclude", r.Exclude, "`file` listing employee numbers or CPR numbers to exclude")
+			fs.BoolVar(&r.AddressWindow, "address-window", r.AddressWindow, "print the recipient address at the envelope window position on every letter, not only those sent by physical mail")
+			fs.StringVar(&r.ReviewFile, "review-file", r.ReviewFile, "also write all letters into this combined, bookmarked PDF")
+			fs.BoolVar(&r.SkipIndividual, "skip-individual", r.SkipIndividual, "write only the review file, not the individual letters")
+			fs.StringVar(&cfg.Output.FileName, "file-name", cfg.Output.FileName, "file name pattern; {campaign} and {Header} placeholders are replaced")
+			fs.IntVar(&cfg.Workers, "workers", cfg.Workers, "number of letters rendered concurrently")
+			fs.BoolVar(&cfg.Preflight.Block, "preflight", cfg.Preflight.Block, "refuse to render if the pre-flight check flags any row")
+		},
+		run: func(cfg config.Config) error {
+			if err := cfg.CheckSecrets(); err != nil {
+				return err
+			}
+			return pdf.GeneratePDFs(cfg.Workbook, cfg.Render.OutputDir, cfg.PDFOptions())
+		},
+	},
+	{
+		name:    "print-batch",
+		summary: "Merge letters for physical mail into print files sorted by postcode",
+		flags: func(fs *flag.FlagSet, cfg *config.Config) {
+			p := &cfg.Print
+			fs.StringVar(&p.OutputDir, "output-dir", p.OutputDir, "directory for the print files and job ticket")
+			fs.IntVar(&p.LettersPerFile, "letters-per-file", p.LettersPerFile, "letters per print file (0 for a single file)")
+			fs.BoolVar(&p.Duplex, "duplex", p.Duplex, "insert blank pages so every letter starts on a new sheet")
+			fs.BoolVar(&p.OMR.Enabled, "omr", p.OMR.Enabled, "print OMR marks for the inserter")
+			fs.Float64Var(&p.OMR.Left, "omr-left", p.OMR.Left, "distance from the left paper edge to the marks, in mm")
+			fs.Float64Var(&p.OMR.Top, "omr-top", p.OMR.Top, "vertical position of the first mark, in mm")
+			fs.Float64Var(&p.OMR.Pitch, "omr-pitch", p.OMR.Pitch, "distance between marks, in mm")
+			fs.Float64Var(&p.OMR.Length, "omr-length", p.OMR.Length, "length of each mark, in mm")
+			fs.Float64Var(&p.OMR.Thickness, "omr-thickness", p.OMR.Thickness, "thickness of each mark, in mm")
+			fs.IntVar(&p.OMR.SequenceBits, "omr-sequence-bits", p.OMR.SequenceBits, "number of sheet sequence marks (0-3)")
+			fs.BoolVar(&p.OMR.Parity, "omr-parity", p.OMR.Parity, "add a parity mark")
+			fs.IntVar(&cfg.Render.Limit, "limit", cfg.Render.Limit, "number of letters to include (0 for all)")
+			fs.StringVar(&cfg.Render.Filter, "filter", cfg.Render.Filter, "select rows with an expression over header names")
+			fs.StringVar(&cfg.Render.Include, "include", cfg.Render.Include, "`file` listing employee numbers or CPR numbers to include")
+			fs.StringVar(&cfg.Render.Exclude, "exclude", cfg.Render.Exclude, "`file` listing employee numbers or CPR numbers to exclude")
+			fs.BoolVar(&cfg.Preflight.Block, "preflight", cfg.Preflight.Block, "refuse to build the batch if the pre-flight check flags any row")
+		},
+		run: func(cfg config.Config) error {
+			_, err := pdf.GeneratePrintBatch(cfg.Workbook, cfg.Print.OutputDir, cfg.PDFOptions(), cfg.BatchOptions())
+			return err
+		},
+	},
+	{
+		name:    "manager-reports",
+		summary: "Write a PDF and workbook per manager listing their team's regulation",
+		flags: func(fs *flag.FlagSet, cfg *config.Config) {
+			fs.StringVar(&cfg.Reports.OutputDir, "output-dir", cfg.Reports.OutputDir, "directory for the manager reports")
+			fs.StringVar(&cfg.Render.Filter, "filter", cfg.Render.Filter, "select rows with an expression over header names")
+			fs.StringVar(&cfg.Render.Include, "include", cfg.Render.Include, "`file` listing employee numbers or CPR numbers to include")
+			fs.StringVar(&cfg.Render.Exclude, "exclude", cfg.Render.Exclude, "`file` listing employee numbers or CPR numbers to exclude")
+		},
+		run: func(cfg config.Config) error {
+			return pdf.GenerateManagerReports(cfg.Workbook, cfg.Reports.OutputDir, cfg.PDFOptions())
+		},
+	},
+	{
+		name:    "pay-equity",
+		summary: "Analyse pay equity between women and men into a PDF and workbook",
+		flags: func(fs *flag.FlagSet, cfg *config.Config) {
+			fs.StringVar(&cfg.Reports.OutputDir, "output-dir", cfg.Reports.OutputDir, "directory for the analysis")
+			fs.StringVar(&cfg.Render.Filter, "filter", cfg.Render.Filter, "select rows with an expression over header names")
+			fs.StringVar(&cfg.Render.Include, "include", cfg.Render.Include, "`file` listing employee numbers or CPR numbers to include")
+			fs.StringVar(&cfg.Render.Exclude, "exclude", cfg.Render.Exclude, "`file` listing employee numbers or CPR numbers to exclude")
+		},
+		run: func(cfg config.Config) error {
+			return pdf.GenerateEquityReport(cfg.Workbook, cfg.Reports.OutputDir, cfg.PDFOptions())
+		},
+	},
+	{
+		name:    "verify",
+		summary: "Check the workbook for missing columns and inconsistent rows",
+		flags:   func(fs *flag.FlagSet, cfg *config.Config) {},
+		run: func(cfg config.Config) error {
+			issues, err := excel.Verify(cfg.Workbook)
+			if err != nil {
+				return err
+			}
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+			if len(issues) > 0 {
+				return fmt.Errorf("%s has %d issue(s)", cfg.Workbook, len(issues))
+			}
+			fmt.Printf("%s is consistent\n", cfg.Workbook)
+			return nil
+		},
+	},
+	{
+		name:    "preflight",
+		summary: "Flag suspicious rows before letters go out",
+		flags: func(fs *flag.FlagSet, cfg *config.Config) {
+			p := &cfg.Preflight
+			fs.Float64Var(&p.MinIncrease, "min-increase", p.MinIncrease, "lowest total increase in percent")
+			fs.Float64Var(&p.MaxIncrease, "max-increase", p.MaxIncrease, "highest total increase in percent")
+			fs.Float64Var(&p.GrossRatioTolerance, "gross-ratio-tolerance", p.GrossRatioTolerance, "allowed deviation in percent of gross/base salary from the department median")
+			fs.StringVar(&p.Report, "report", p.Report, "`file` the issues are written to (empty for none)")
+		},
+		run: func(cfg config.Config) error {
+			employees, err := excel.ReadEmployees(cfg.Workbook)
+			if err != nil {
+				return err
+			}
+			issues := excel.Preflight(employees, cfg.PreflightRules())
+			if err := preflightReport(os.Stdout, cfg.Workbook, len(employees), issues); err != nil {
+				return err
+			}
+			if cfg.Preflight.Report != "" {
+				file, err := os.Create(cfg.Preflight.Report)
+				if err != nil {
+					return fmt.Errorf("failed to create report: %v", err)
+				}
+				defer file.Close()
+				if err := preflightReport(file, cfg.Workbook, len(employees), issues); err != nil {
+					return err
+				}
+				fmt.Printf("Wrote %s\n", cfg.Preflight.Report)
+			}
+			if len(issues) > 0 {
+				return fmt.Errorf("pre-flight check flagged %d issue(s)", len(issues))
+			}
+			return nil
+		},
+	},
+	{
+		name:    "check-budgets",
+		summary: "Compare individual adjustments per department with the budgets",
+		flags:   func(fs *flag.FlagSet, cfg *config.Config) {},
+		run: func(cfg config.Config) error {
+			budgets := cfg.ExcelOptions().Budgets
+			if len(budgets) == 0 {
+				return fmt.Errorf("no budgets configured; add a budgets section to the config file")
+			}
+			employees, err := excel.ReadEmployees(cfg.Workbook)
+			if err != nil {
+				return err
+			}
+			return checkBudgets(excel.CheckBudgets(employees, budgets))
+		},
+	},
+	{
+		name:    "inspect",
+		summary: "Print a summary of the workbook contents",
+		flags:   func(fs *flag.FlagSet, cfg *config.Config) {},
+		run: func(cfg config.Config) error {
+			employees, err := excel.ReadEmployees(cfg.Workbook)
+			if err != nil {
+				return err
+			}
+			inspect(cfg.Workbook, employees)
+			return nil
+		},
+	},
+}
+
+// inspect prints row counts per category and salary ranges
+func inspect(workbook string, employees []models.EmployeeData) {
+	fmt.Printf("%s: %d employees\n", workbook, len(employees))
+
+	for _, header := range []string{"LetterType", "Agreement", "EmploymentType", "Department", "Role", "SecurityLevel", "DeliveryMethod"} {
+		counts := make(map[string]int)
+		for _, emp := range employees {
+			value, _ := emp.Field(header)
+			counts[value]++
+		}
+		values := make([]string, 0, len(counts))
+		for value := range counts {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+
+		fmt.Printf("\n%s:\n", header)
+		for _, value := range values {
+			fmt.Printf("  %-28s %6d\n", value, counts[value])
+		}
+	}
+
+	fmt.Printf("\n%-22s %12s %12s %12s\n", "Column", "Min", "Average", "Max")
+	for _, header := range []string{"HoursPerWeek", "Seniority", "SalaryStep", "BaseSalary", "Supplements", "NewBaseSalary", "FullTimeBaseSalary", "GrossSalary", "NewGrossSalary", "GeneralAdjustment", "IndividualAdjustment", "PercentageIncrease", "BackPay", "PensionRate", "PensionContribution", "NewPensionContribution"} {
+		var minValue, maxValue, sum float64
+		count := 0
+		for _, emp := range employees {
+			value, _ := emp.Field(header)
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			if count == 0 || v < minValue {
+				minValue = v
+			}
+			if count == 0 || v > maxValue {
+				maxValue = v
+			}
+			sum += v
+			count++
+		}
+		if count > 0 {
+			fmt.Printf("%-22s %12.2f %12.2f %12.2f\n", header, minValue, sum/float64(count), maxValue)
+		}
+	}
+}
+
+// checkBudgets prints the budget use of each department and fails if any
+// department is over budget
+func checkBudgets(uses []excel.BudgetUse) error {
+	fmt.Printf("%-18s %9s %14s %12s %12s %12s\n", "Department", "Employees", "Payroll", "Pool", "Used", "Remaining")
+	over := 0
+	for _, u := range uses {
+		status := ""
+		if u.Over() {
+			status = "  OVER BUDGET"
+			over++
+		}
+		fmt.Printf("%-18s %9d %14.2f %12.2f %12.2f %12.2f%s\n",
+			u.Department, u.Employees, u.Payroll, u.Pool, u.Used, u.Remaining(), status)
+	}
+	if over > 0 {
+		return fmt.Errorf("%d department(s) over budget", over)
+	}
+	return nil
+}
+
+// preflightReport writes the pre-flight issues, preceded by their number per column
+func preflightReport(w io.Writer, workbook string, rows int, issues []excel.Issue) error {
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		counts[issue.Column]++
+	}
+	columns := make([]string, 0, len(counts))
+	for column := range counts {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	if _, err := fmt.Fprintf(w, "Pre-flight check of %s: %d rows, %d issue(s)\n", workbook, rows, len(issues)); err != nil {
+		return err
+	}
+	for _, column := range columns {
+		fmt.Fprintf(w, "  %-16s %d\n", column, counts[column])
+	}
+	if len(issues) > 0 {
+		fmt.Fprintln(w)
+	}
+	for _, issue := range issues {
+		fmt.Fprintln(w, issue)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+// Command dsb-gen generates mock employee data and renders the salary letters.
+//
+// Usage:
+//
+//	dsb-gen <command> [flags]
+//
+// Run `dsb-gen help` for the list of commands and `dsb-gen <command> -help`
+// for the flags of a command. Every flag can also be set in the config file
+// (dsb.yaml by default) or through a DSB_* environment variable.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"dsb-excel-generator/pkg/config"
+)
+
+// command is a dsb-gen subcommand
+type command struct {
+	name    string
+	summary string
+	// flags registers the command's flags, bound to fields of cfg
+	flags func(fs *flag.FlagSet, cfg *config.Config)
+	run   func(cfg config.Config) error
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		if len(args) > 1 {
+			return run([]string{args[1], "-help"})
+		}
+		printUsage()
+		return nil
+	}
+
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		printUsage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	cfg, err := settings(cmd, args[1:])
+	if err != nil {
+		return err
+	}
+	return cmd.run(cfg)
+}
+
+// settings reads the command's settings from the config file, DSB_*
+// environment variables and the flags in args, and validates them
+func settings(cmd *command, args []string) (config.Config, error) {
+	cfgPath, explicit := configPath(args)
+	cfg := config.Default()
+	if _, err := os.Stat(cfgPath); err == nil || explicit {
+		var err error
+		if cfg, err = config.Load(cfgPath); err != nil {
+			return cfg, err
+		}
+	}
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.String("config", cfgPath, "YAML config file")
+	fs.StringVar(&cfg.Workbook, "workbook", cfg.Workbook, "Excel workbook with the employee data")
+	fs.StringVar(&cfg.Campaign.Name, "campaign", cfg.Campaign.Name, "campaign title used in letters, metadata and file names")
+	fs.IntVar(&cfg.Campaign.Year, "year", cfg.Campaign.Year, "campaign year")
+	fs.StringVar(&cfg.Campaign.Agreement, "agreement", cfg.Campaign.Agreement, "code of the default agreement, used for employees without one, e.g. HK")
+	fs.StringVar(&cfg.Campaign.AgreementEffectiveDate, "agreement-effective-date", cfg.Campaign.AgreementEffectiveDate, "override the default agreement's effective date, e.g. 2025-05-01 or \"1. maj 2025\"")
+	fs.StringVar(&cfg.Campaign.PayoutMonth, "payout-month", cfg.Campaign.PayoutMonth, "Danish name of the payout month, e.g. juni")
+	cmd.flags(fs, &cfg)
+	fs.Usage = func() { printCommandUsage(cmd, fs) }
+
+	// Environment variables override the config file, flags override both
+	year := cfg.Campaign.Year
+	if err := applyEnv(fs); err != nil {
+		return cfg, err
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if fs.NArg() > 0 {
+		return cfg, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	cfg.FollowYear(year)
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid settings: %v", err)
+	}
+	return cfg, nil
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// configPath finds the config file from the -config flag or DSB_CONFIG.
+// It reports whether the file was given explicitly, in which case it must exist.
+func configPath(args []string) (string, bool) {
+	for i, arg := range args {
+		name := strings.TrimLeft(arg, "-")
+		if name == "config" && i+1 < len(args) {
+			return args[i+1], true
+		}
+		if strings.HasPrefix(name, "config=") {
+			return strings.TrimPrefix(name, "config="), true
+		}
+	}
+	if path := os.Getenv("DSB_CONFIG"); path != "" {
+		return path, true
+	}
+	return config.DefaultFile, false
+}
+
+// envName returns the environment variable for a flag, e.g. DSB_OUTPUT_DIR for -output-dir
+func envName(flagName string) string {
+	return "DSB_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// applyEnv sets flags from their DSB_* environment variables
+func applyEnv(fs *flag.FlagSet) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" || err != nil {
+			return
+		}
+		if value, ok := os.LookupEnv(envName(f.Name)); ok {
+			if setErr := fs.Set(f.Name, value); setErr != nil {
+				err = fmt.Errorf("invalid %s: %v", envName(f.Name), setErr)
+			}
+		}
+	})
+	return err
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: dsb-gen <command> [flags]\n\nCommands:\n")
+	width := 0
+	for _, cmd := range commands {
+		width = max(width, len(cmd.name))
+	}
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-*s  %s\n", width, cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun 'dsb-gen <command> -help' for the flags of a command.\n")
+	fmt.Fprintf(os.Stderr, "Settings are read from %s (or -config / DSB_CONFIG), then DSB_* environment variables, then flags.\n", config.DefaultFile)
+}
+
+func printCommandUsage(cmd *command, fs *flag.FlagSet) {
+	fmt.Fprintf(os.Stderr, "Usage: dsb-gen %s [flags]\n\n%s\n\nFlags:\n", cmd.name, cmd.summary)
+
+	var flags []*flag.Flag
+	fs.VisitAll(func(f *flag.Flag) { flags = append(flags, f) })
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+
+	for _, f := range flags {
+		name, usage := flag.UnquoteUsage(f)
+		fmt.Fprintf(os.Stderr, "  -%s %s\n    \t%s", f.Name, name, usage)
+		if f.DefValue != "" && f.DefValue != "0" && f.DefValue != "false" {
+			if _, isString := f.Value.(flag.Getter).Get().(string); isString {
+				fmt.Fprintf(os.Stderr, " (default %q)", f.DefValue)
+			} else {
+				fmt.Fprintf(os.Stderr, " (default %s)", f.DefValue)
+			}
+		}
+		if f.Name == "config" {
+			fmt.Fprintf(os.Stderr, " [DSB_CONFIG]\n")
+		} else {
+			fmt.Fprintf(os.Stderr, " [%s]\n", envName(f.Name))
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSettingsPrecedence(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "dsb.yaml")
+	yaml := "version: 1\nrender:\n  limit: 5\n  output_dir: from-file\n"
+	if err := os.WriteFile(filename, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		env       map[string]string
+		args      []string
+		limit     int
+		outputDir string
+	}{
+		{"file", nil, nil, 5, "from-file"},
+		{"env over file", map[string]string{"DSB_LIMIT": "7"}, nil, 7, "from-file"},
+		{"flags over env", map[string]string{"DSB_LIMIT": "7", "DSB_OUTPUT_DIR": "from-env"},
+			[]string{"-limit", "9"}, 9, "from-env"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			cfg, err := settings(findCommand("render"), append([]string{"-config", filename}, tt.args...))
+			if err != nil {
+				t.Fatalf("settings: %v", err)
+			}
+			if cfg.Render.Limit != tt.limit || cfg.Render.OutputDir != tt.outputDir {
+				t.Errorf("limit %d, output dir %q, want %d, %q", cfg.Render.Limit, cfg.Render.OutputDir, tt.limit, tt.outputDir)
+			}
+		})
+	}
+}
+
+func TestSettingsYearFlag(t *testing.T) {
+	t.Setenv("DSB_CONFIG", "")
+	cfg, err := settings(findCommand("generate-data"), []string{"-year", "2026"})
+	if err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+	if cfg.Campaign.Name != "Lønregulering 2026" || cfg.Agreements[0].EffectiveDate != "2026-05-01" {
+		t.Errorf("campaign %q with %s effective %s, want Lønregulering 2026 from 2026-05-01",
+			cfg.Campaign.Name, cfg.Agreements[0].Code, cfg.Agreements[0].EffectiveDate)
+	}
+	cfg, err = settings(findCommand("generate-data"), []string{"-year", "2026", "-campaign", "Særlig regulering"})
+	if err != nil || cfg.Campaign.Name != "Særlig regulering" {
+		t.Errorf("settings with an explicit title: campaign %q, %v", cfg.Campaign.Name, err)
+	}
+}
@@ -0,0 +1,161 @@
+// Package calendar computes Danish public holidays, business days and
+// banking days. Dates are time.Time values at midnight UTC; the time of
+// day of arguments is ignored.
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// Holiday is a named day off
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// storeBededagAbolished is the first year without Store bededag as a public holiday
+const storeBededagAbolished = 2024
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func truncate(t time.Time) time.Time {
+	return date(t.Year(), t.Month(), t.Day())
+}
+
+// Easter returns Easter Sunday of the year in the Gregorian calendar
+func Easter(year int) time.Time {
+	// Anonymous Gregorian algorithm (Meeus/Jones/Butcher)
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// Holidays returns the Danish public holidays of the year in date order
+func Holidays(year int) []Holiday {
+	easter := Easter(year)
+	fromEaster := func(days int) time.Time { return easter.AddDate(0, 0, days) }
+
+	holidays := []Holiday{
+		{date(year, time.January, 1), "Nytårsdag"},
+		{fromEaster(-3), "Skærtorsdag"},
+		{fromEaster(-2), "Langfredag"},
+		{easter, "Påskedag"},
+		{fromEaster(1), "2. påskedag"},
+		{fromEaster(39), "Kristi himmelfartsdag"},
+		{fromEaster(49), "Pinsedag"},
+		{fromEaster(50), "2. pinsedag"},
+		{date(year, time.December, 25), "Juledag"},
+		{date(year, time.December, 26), "2. juledag"},
+	}
+	if year < storeBededagAbolished {
+		holidays = append(holidays, Holiday{fromEaster(26), "Store bededag"})
+	}
+	sortHolidays(holidays)
+	return holidays
+}
+
+// BankHolidays returns the days Danish banks are closed on weekdays: the
+// public holidays plus Grundlovsdag, the day after Kristi himmelfartsdag,
+// Juleaftensdag and Nytårsaftensdag.
+func BankHolidays(year int) []Holiday {
+	holidays := append(Holidays(year),
+		Holiday{date(year, time.June, 5), "Grundlovsdag"},
+		Holiday{Easter(year).AddDate(0, 0, 40), "Fredag efter Kristi himmelfartsdag"},
+		Holiday{date(year, time.December, 24), "Juleaftensdag"},
+		Holiday{date(year, time.December, 31), "Nytårsaftensdag"},
+	)
+	sortHolidays(holidays)
+	return holidays
+}
+
+func sortHolidays(holidays []Holiday) {
+	sort.SliceStable(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+}
+
+func find(holidays []Holiday, t time.Time) (string, bool) {
+	t = truncate(t)
+	for _, h := range holidays {
+		if h.Date.Equal(t) {
+			return h.Name, true
+		}
+	}
+	return "", false
+}
+
+// HolidayName returns the name of the public holiday on t, if any
+func HolidayName(t time.Time) (string, bool) {
+	return find(Holidays(t.Year()), t)
+}
+
+// IsHoliday reports whether t is a Danish public holiday
+func IsHoliday(t time.Time) bool {
+	_, ok := HolidayName(t)
+	return ok
+}
+
+// IsWeekend reports whether t is a Saturday or Sunday
+func IsWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// IsBusinessDay reports whether t is a weekday that is not a public holiday
+func IsBusinessDay(t time.Time) bool {
+	return !IsWeekend(t) && !IsHoliday(t)
+}
+
+// IsBankingDay reports whether Danish banks are open on t
+func IsBankingDay(t time.Time) bool {
+	if IsWeekend(t) {
+		return false
+	}
+	_, closed := find(BankHolidays(t.Year()), t)
+	return !closed
+}
+
+// NextNonHoliday returns t, or the first day after t that is not a public holiday
+func NextNonHoliday(t time.Time) time.Time {
+	t = truncate(t)
+	for IsHoliday(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// NextBusinessDay returns t, or the first business day after t
+func NextBusinessDay(t time.Time) time.Time {
+	t = truncate(t)
+	for !IsBusinessDay(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// PreviousBankingDay returns t, or the last banking day before t
+func PreviousBankingDay(t time.Time) time.Time {
+	t = truncate(t)
+	for !IsBankingDay(t) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t
+}
+
+// LastBankingDay returns the last banking day of the month, the usual
+// Danish payday for monthly salaries ("ultimo")
+func LastBankingDay(year int, month time.Month) time.Time {
+	return PreviousBankingDay(date(year, month+1, 0))
+}
@@ -0,0 +1,91 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEaster(t *testing.T) {
+	tests := []struct {
+		year int
+		want time.Time
+	}{
+		{2024, date(2024, time.March, 31)},
+		{2025, date(2025, time.April, 20)},
+		{2038, date(2038, time.April, 25)},
+		{1818, date(1818, time.March, 22)},
+		{2285, date(2285, time.March, 22)},
+	}
+	for _, tt := range tests {
+		if got := Easter(tt.year); !got.Equal(tt.want) {
+			t.Errorf("Easter(%d) = %s, want %s", tt.year, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestStoreBededag(t *testing.T) {
+	tests := []struct {
+		day  time.Time
+		want bool
+	}{
+		{date(2023, time.May, 5), true},
+		{date(2024, time.April, 26), false},
+		{date(2025, time.May, 16), false},
+	}
+	for _, tt := range tests {
+		name, ok := HolidayName(tt.day)
+		if ok != tt.want || (ok && name != "Store bededag") {
+			t.Errorf("HolidayName(%s) = %q, %v, want Store bededag: %v", tt.day.Format(time.DateOnly), name, ok, tt.want)
+		}
+	}
+	for _, year := range []int{storeBededagAbolished - 1, storeBededagAbolished} {
+		found := false
+		for _, h := range Holidays(year) {
+			found = found || h.Name == "Store bededag"
+		}
+		if want := year < storeBededagAbolished; found != want {
+			t.Errorf("Holidays(%d) includes Store bededag: %v, want %v", year, found, want)
+		}
+	}
+}
+
+func TestLastBankingDay(t *testing.T) {
+	tests := []struct {
+		name  string
+		year  int
+		month time.Month
+		want  time.Time
+	}{
+		// 31 May is a Saturday, 30 May the bank holiday after Kristi himmelfartsdag on 29 May
+		{"May 2025", 2025, time.May, date(2025, time.May, 28)},
+		// 31 August is a Sunday
+		{"August 2025", 2025, time.August, date(2025, time.August, 29)},
+		// 31 December is Nytårsaftensdag
+		{"December 2025", 2025, time.December, date(2025, time.December, 30)},
+		// 31 December is a Sunday and 30 December a Saturday
+		{"December 2023", 2023, time.December, date(2023, time.December, 29)},
+		// 30 April is a Wednesday
+		{"April 2025", 2025, time.April, date(2025, time.April, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LastBankingDay(tt.year, tt.month)
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+			}
+			if !IsBankingDay(got) {
+				t.Errorf("%s is not a banking day", got.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestIgnoresTimeOfDay(t *testing.T) {
+	christmas := time.Date(2025, time.December, 25, 15, 30, 0, 0, time.UTC)
+	if !IsHoliday(christmas) {
+		t.Error("Juledag in the afternoon is not a holiday")
+	}
+	if got, want := NextBusinessDay(christmas), date(2025, time.December, 29); !got.Equal(want) {
+		t.Errorf("NextBusinessDay = %s, want %s", got.Format(time.DateOnly), want.Format(time.DateOnly))
+	}
+}
@@ -0,0 +1,465 @@
+// Package config holds the settings for a generator run. Settings come from
+// defaults, an optional YAML config file, DSB_* environment variables and
+// command-line flags, in increasing order of precedence.
+//
+// A config file describes a campaign (e.g. Lønregulering 2025, pension
+// changes, annual reviews) and is versioned so that files stay valid as
+// the format evolves. It is validated on load.
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"dsb-excel-generator/pkg/excel"
+	"dsb-excel-generator/pkg/models"
+	"dsb-excel-generator/pkg/pdf"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the config file read when none is given explicitly
+const DefaultFile = "dsb.yaml"
+
+// Version is the current config file format version
+const Version = 1
+
+// Config is the complete configuration of a run
+type Config struct {
+	// Version of the config file format; must be Version
+	Version  int            `yaml:"version"`
+	Campaign CampaignConfig `yaml:"campaign"`
+	// Agreements are the collective agreements covered by the campaign
+	Agreements []AgreementConfig `yaml:"agreements"`
+	// Budgets are the departments' pools for individual adjustments
+	Budgets []BudgetConfig `yaml:"budgets"`
+	// Workbook is the Excel file written by generate-data and read by the other commands
+	Workbook  string          `yaml:"workbook"`
+	Data      DataConfig      `yaml:"data"`
+	Render    RenderConfig    `yaml:"render"`
+	Print     PrintConfig     `yaml:"print"`
+	Reports   ReportsConfig   `yaml:"reports"`
+	Preflight PreflightConfig `yaml:"preflight"`
+	Output    OutputConfig    `yaml:"output"`
+	Security  SecurityConfig  `yaml:"security"`
+	// Workers is the number of letters rendered concurrently
+	Workers int `yaml:"workers"`
+}
+
+// CampaignConfig describes the campaign the letters belong to
+type CampaignConfig struct {
+	// Name is the letter title, e.g. "Lønregulering 2025"
+	Name string `yaml:"name"`
+	Year int    `yaml:"year"`
+	// Agreement is the code of the default agreement, used for employees
+	// without one, e.g. "HK"
+	Agreement string `yaml:"agreement"`
+	// AgreementEffectiveDate optionally overrides the effective date of the
+	// default agreement
+	AgreementEffectiveDate string `yaml:"agreement_effective_date"`
+	// PayoutMonth is a Danish month name, e.g. "juni"
+	PayoutMonth string `yaml:"payout_month"`
+}
+
+// AgreementConfig defines a collective agreement and its general regulation.
+// Percentages are written as numbers, e.g. 2.5 for 2.5%.
+type AgreementConfig struct {
+	Code                string  `yaml:"code"`
+	Name                string  `yaml:"name"`
+	EffectiveDate       string  `yaml:"effective_date"`
+	GeneralIncrease     float64 `yaml:"general_increase"`
+	PensionIncrease     float64 `yaml:"pension_increase"`
+	EmployeePensionRate float64 `yaml:"employee_pension_rate"`
+	EmployerPensionRate float64 `yaml:"employer_pension_rate"`
+}
+
+// BudgetConfig is a department's monthly pool for individual adjustments:
+// an amount in kroner, or a percentage of the department's base salaries
+type BudgetConfig struct {
+	Department string  `yaml:"department"`
+	Amount     float64 `yaml:"amount"`
+	Percent    float64 `yaml:"percent"`
+}
+
+// CampaignModel returns the campaign as passed to the generators.
+// The config must be valid.
+func (c Config) CampaignModel() models.Campaign {
+	month, _ := models.ParseDanishMonth(c.Campaign.PayoutMonth)
+	override, _ := parseOptionalDate(c.Campaign.AgreementEffectiveDate)
+
+	campaign := models.Campaign{
+		Title:       c.Campaign.Name,
+		Year:        c.Campaign.Year,
+		Agreement:   c.Campaign.Agreement,
+		PayoutMonth: month,
+	}
+	for _, a := range c.Agreements {
+		effective, _ := models.ParseDate(a.EffectiveDate)
+		if a.Code == c.Campaign.Agreement && !override.IsZero() {
+			effective = override
+		}
+		campaign.Agreements = append(campaign.Agreements, models.Agreement{
+			Code:                a.Code,
+			Name:                a.Name,
+			EffectiveDate:       effective,
+			GeneralIncrease:     a.GeneralIncrease,
+			PensionIncrease:     a.PensionIncrease,
+			EmployeePensionRate: a.EmployeePensionRate,
+			EmployerPensionRate: a.EmployerPensionRate,
+		})
+	}
+	return campaign
+}
+
+// DataConfig configures the mock data generation
+type DataConfig struct {
+	Rows int   `yaml:"rows"`
+	Seed int64 `yaml:"seed"`
+	// EffectiveDates are the rules effective dates are drawn from, by weight
+	EffectiveDates []DateRuleConfig `yaml:"effective_dates"`
+	// Templates are the letter types of the campaign, drawn by weight
+	Templates []TemplateConfig `yaml:"templates"`
+	// Agreements are the agreements employees are assigned to, drawn by weight
+	Agreements []AgreementWeightConfig `yaml:"agreements"`
+	// AllocateBudgets keeps each department's individual adjustments within its budget
+	AllocateBudgets bool `yaml:"allocate_budgets"`
+	// Formulas writes the derived salary columns as Excel formulas
+	Formulas bool `yaml:"formulas"`
+	// HighlightIncrease is the individual increase, in percent, highlighted for review
+	HighlightIncrease float64 `yaml:"highlight_increase"`
+	// Protect locks the workbook except the columns HR fills in
+	Protect bool `yaml:"protect"`
+}
+
+// DateRuleConfig is a rule for drawing effective dates: either a fixed date,
+// or a window from which the first day of a month is drawn. Dates may be
+// written as ISO dates (2025-03-01) or in Danish (1. marts 2025).
+type DateRuleConfig struct {
+	Date   string `yaml:"date"`
+	From   string `yaml:"from"`
+	To     string `yaml:"to"`
+	Weight int    `yaml:"weight"`
+}
+
+// Rule returns the rule as used by the generator. The config must be valid.
+func (r DateRuleConfig) Rule() excel.DateRule {
+	rule := excel.DateRule{Weight: r.Weight}
+	rule.Date, _ = parseOptionalDate(r.Date)
+	rule.From, _ = parseOptionalDate(r.From)
+	rule.To, _ = parseOptionalDate(r.To)
+	return rule
+}
+
+// parseOptionalDate parses a date, returning the zero date for empty text
+func parseOptionalDate(s string) (models.Date, error) {
+	var d models.Date
+	err := d.UnmarshalText([]byte(s))
+	return d, err
+}
+
+// TemplateConfig enables a letter template with a relative weight
+type TemplateConfig struct {
+	LetterType string `yaml:"letter_type"`
+	Weight     int    `yaml:"weight"`
+}
+
+// AgreementWeightConfig assigns employees to an agreement with a relative weight
+type AgreementWeightConfig struct {
+	Agreement string `yaml:"agreement"`
+	Weight    int    `yaml:"weight"`
+}
+
+// OutputConfig configures the naming of generated files
+type OutputConfig struct {
+	// FileName is the pattern for individual letters, see pdf.Options.FileNamePattern
+	FileName string `yaml:"file_name"`
+}
+
+// SecurityConfig configures password protection of letters and the workbook
+type SecurityConfig struct {
+	ProtectLevels []string `yaml:"protect_levels"`
+	// OwnerPasswordEnv names the environment variable holding the owner password,
+	// so the password itself is never stored in the config file
+	OwnerPasswordEnv string `yaml:"owner_password_env"`
+	// WorkbookPasswordEnv names the environment variable holding the password
+	// of a protected workbook; if it is not set the workbook has no password
+	WorkbookPasswordEnv string `yaml:"workbook_password_env"`
+	UserPassword        string `yaml:"user_password"`
+	AllowPrint          bool   `yaml:"allow_print"`
+	AllowCopy           bool   `yaml:"allow_copy"`
+}
+
+// RenderConfig configures the individual PDF letters
+type RenderConfig struct {
+	OutputDir      string `yaml:"output_dir"`
+	Limit          int    `yaml:"limit"`
+	Sample         int    `yaml:"sample"`
+	Filter         string `yaml:"filter"`
+	Include        string `yaml:"include"`
+	Exclude        string `yaml:"exclude"`
+	AddressWindow  bool   `yaml:"address_window"`
+	ReviewFile     string `yaml:"review_file"`
+	SkipIndividual bool   `yaml:"skip_individual"`
+}
+
+// PrintConfig configures the print-house batch
+type PrintConfig struct {
+	OutputDir      string    `yaml:"output_dir"`
+	LettersPerFile int       `yaml:"letters_per_file"`
+	Duplex         bool      `yaml:"duplex"`
+	OMR            OMRConfig `yaml:"omr"`
+}
+
+// ReportsConfig configures the manager reports
+type ReportsConfig struct {
+	OutputDir string `yaml:"output_dir"`
+}
+
+// PreflightConfig configures the pre-flight check of the workbook.
+// Increases and the tolerance are in percent.
+type PreflightConfig struct {
+	MinIncrease         float64 `yaml:"min_increase"`
+	MaxIncrease         float64 `yaml:"max_increase"`
+	GrossRatioTolerance float64 `yaml:"gross_ratio_tolerance"`
+	// Report is the file the issues are written to; empty writes no file
+	Report string `yaml:"report"`
+	// Block refuses to render letters and print batches if the check flags any row
+	Block bool `yaml:"block"`
+}
+
+// OMRConfig configures the OMR marks of the print batch, in mm
+type OMRConfig struct {
+	Enabled      bool    `yaml:"enabled"`
+	Left         float64 `yaml:"left"`
+	Top          float64 `yaml:"top"`
+	Pitch        float64 `yaml:"pitch"`
+	Length       float64 `yaml:"length"`
+	Thickness    float64 `yaml:"thickness"`
+	SequenceBits int     `yaml:"sequence_bits"`
+	Parity       bool    `yaml:"parity"`
+}
+
+// Default returns the configuration used when nothing else is set
+func Default() Config {
+	return defaultFor(models.DefaultCampaign().Year)
+}
+
+// defaultFor returns the default configuration of a campaign year
+func defaultFor(year int) Config {
+	omr := pdf.DefaultOMROptions()
+	data := excel.DefaultOptions()
+	data.Campaign = models.NewCampaign(year)
+	data.EffectiveDates = excel.DefaultEffectiveDates(year)
+	preflight := excel.DefaultPreflightRules()
+	campaign := data.Campaign
+
+	cfg := Config{
+		Version: Version,
+		Campaign: CampaignConfig{
+			Name:        campaign.Title,
+			Year:        campaign.Year,
+			Agreement:   campaign.Agreement,
+			PayoutMonth: models.DanishMonths[campaign.PayoutMonth-1],
+		},
+		Workbook: "dsb-mock-data-excel.xlsx",
+		Data: DataConfig{
+			Rows:              data.Rows,
+			HighlightIncrease: data.HighlightIncrease,
+		},
+		Render: RenderConfig{
+			OutputDir: "output_pdfs",
+			Limit:     10,
+		},
+		Print: PrintConfig{
+			OutputDir:      "output_print",
+			LettersPerFile: 500,
+			Duplex:         true,
+			OMR: OMRConfig{
+				Enabled:      omr.Enabled,
+				Left:         omr.Left,
+				Top:          omr.Top,
+				Pitch:        omr.Pitch,
+				Length:       omr.Length,
+				Thickness:    omr.Thickness,
+				SequenceBits: omr.SequenceBits,
+				Parity:       omr.Parity,
+			},
+		},
+		Reports: ReportsConfig{OutputDir: "output_reports"},
+		Preflight: PreflightConfig{
+			MinIncrease:         preflight.MinIncrease,
+			MaxIncrease:         preflight.MaxIncrease,
+			GrossRatioTolerance: preflight.GrossRatioTolerance,
+			Report:              "preflight-report.txt",
+		},
+		Output: OutputConfig{FileName: pdf.DefaultFileNamePattern},
+		Security: SecurityConfig{
+			OwnerPasswordEnv:    "DSB_PDF_OWNER_PASSWORD",
+			WorkbookPasswordEnv: "DSB_WORKBOOK_PASSWORD",
+			UserPassword:        pdf.UserPasswordNone,
+			AllowPrint:          true,
+		},
+		Workers: pdf.DefaultWorkers,
+	}
+	for _, r := range data.EffectiveDates {
+		cfg.Data.EffectiveDates = append(cfg.Data.EffectiveDates, DateRuleConfig{
+			Date:   r.Date.Danish(),
+			From:   r.From.String(),
+			To:     r.To.String(),
+			Weight: r.Weight,
+		})
+	}
+	for _, t := range data.LetterTypes {
+		cfg.Data.Templates = append(cfg.Data.Templates, TemplateConfig{LetterType: t.Value, Weight: t.Weight})
+	}
+	for _, a := range campaign.Agreements {
+		cfg.Agreements = append(cfg.Agreements, AgreementConfig{
+			Code:                a.Code,
+			Name:                a.Name,
+			EffectiveDate:       a.EffectiveDate.String(),
+			GeneralIncrease:     a.GeneralIncrease,
+			PensionIncrease:     a.PensionIncrease,
+			EmployeePensionRate: a.EmployeePensionRate,
+			EmployerPensionRate: a.EmployerPensionRate,
+		})
+	}
+	for _, a := range data.Agreements {
+		cfg.Data.Agreements = append(cfg.Data.Agreements, AgreementWeightConfig{Agreement: a.Value, Weight: a.Weight})
+	}
+	return cfg
+}
+
+// Load reads a YAML config file on top of the defaults and validates it.
+// Unknown keys are reported as errors to catch typos.
+func Load(filename string) (Config, error) {
+	cfg := Default()
+	// The version must be stated in the file
+	cfg.Version = 0
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && err != io.EOF {
+		return cfg, fmt.Errorf("invalid config file %s: %v", filename, err)
+	}
+	cfg.FollowYear(Default().Campaign.Year)
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %v", filename, err)
+	}
+
+	return cfg, nil
+}
+
+// FollowYear moves the settings derived from the campaign year from the
+// defaults of year from to those of the current campaign year: the campaign
+// name, the agreements and the effective date rules. Settings that differ
+// from the defaults were set explicitly and are kept.
+func (c *Config) FollowYear(from int) {
+	if c.Campaign.Year == from {
+		return
+	}
+	old, current := defaultFor(from), defaultFor(c.Campaign.Year)
+	if c.Campaign.Name == old.Campaign.Name {
+		c.Campaign.Name = current.Campaign.Name
+	}
+	if slices.Equal(c.Agreements, old.Agreements) {
+		c.Agreements = current.Agreements
+	}
+	if slices.Equal(c.Data.EffectiveDates, old.Data.EffectiveDates) {
+		c.Data.EffectiveDates = current.Data.EffectiveDates
+	}
+}
+
+// ExcelOptions returns the options for excel.Generate
+func (c Config) ExcelOptions() excel.Options {
+	opts := excel.Options{
+		Rows:     c.Data.Rows,
+		Seed:     c.Data.Seed,
+		Campaign: c.CampaignModel(),
+	}
+	for _, r := range c.Data.EffectiveDates {
+		opts.EffectiveDates = append(opts.EffectiveDates, r.Rule())
+	}
+	for _, t := range c.Data.Templates {
+		opts.LetterTypes = append(opts.LetterTypes, excel.Weighted{Value: t.LetterType, Weight: t.Weight})
+	}
+	for _, a := range c.Data.Agreements {
+		opts.Agreements = append(opts.Agreements, excel.Weighted{Value: a.Agreement, Weight: a.Weight})
+	}
+	for _, b := range c.Budgets {
+		opts.Budgets = append(opts.Budgets, excel.Budget{Department: b.Department, Amount: b.Amount, Percent: b.Percent})
+	}
+	opts.AllocateBudgets = c.Data.AllocateBudgets
+	opts.Formulas = c.Data.Formulas
+	opts.HighlightIncrease = c.Data.HighlightIncrease
+	opts.Protect = c.Data.Protect
+	if c.Data.Protect && c.Security.WorkbookPasswordEnv != "" {
+		opts.Password = os.Getenv(c.Security.WorkbookPasswordEnv)
+	}
+	return opts
+}
+
+// PreflightRules returns the bounds of the pre-flight check
+func (c Config) PreflightRules() excel.PreflightRules {
+	return excel.PreflightRules{
+		MinIncrease:         c.Preflight.MinIncrease,
+		MaxIncrease:         c.Preflight.MaxIncrease,
+		GrossRatioTolerance: c.Preflight.GrossRatioTolerance,
+		Campaign:            c.CampaignModel(),
+	}
+}
+
+// PDFOptions returns the options for pdf.GeneratePDFs
+func (c Config) PDFOptions() pdf.Options {
+	opts := pdf.Options{
+		Limit:           c.Render.Limit,
+		Sample:          c.Render.Sample,
+		Filter:          c.Render.Filter,
+		IncludeFile:     c.Render.Include,
+		ExcludeFile:     c.Render.Exclude,
+		AddressWindow:   c.Render.AddressWindow,
+		ReviewFile:      c.Render.ReviewFile,
+		SkipIndividual:  c.Render.SkipIndividual,
+		Campaign:        c.CampaignModel(),
+		FileNamePattern: c.Output.FileName,
+		Workers:         c.Workers,
+		Security: pdf.SecurityOptions{
+			ProtectLevels: c.Security.ProtectLevels,
+			OwnerPassword: os.Getenv(c.Security.OwnerPasswordEnv),
+			UserPassword:  c.Security.UserPassword,
+			AllowPrint:    c.Security.AllowPrint,
+			AllowCopy:     c.Security.AllowCopy,
+		},
+	}
+	if c.Preflight.Block {
+		rules := c.PreflightRules()
+		opts.Preflight = &rules
+	}
+	return opts
+}
+
+// BatchOptions returns the options for pdf.GeneratePrintBatch
+func (c Config) BatchOptions() pdf.BatchOptions {
+	omr := c.Print.OMR
+	return pdf.BatchOptions{
+		LettersPerFile: c.Print.LettersPerFile,
+		Duplex:         c.Print.Duplex,
+		OMR: pdf.OMROptions{
+			Enabled:      omr.Enabled,
+			Left:         omr.Left,
+			Top:          omr.Top,
+			Pitch:        omr.Pitch,
+			Length:       omr.Length,
+			Thickness:    omr.Thickness,
+			SequenceBits: omr.SequenceBits,
+			Parity:       omr.Parity,
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"version only", "version: 1\n", ""},
+		{"empty file", "", "version: is required"},
+		{"missing version", "campaign:\n  name: Lønregulering\n", "version: is required"},
+		{"unsupported version", "version: 2\n", "unsupported version 2"},
+		{"unknown key", "version: 1\ncampain:\n  name: Lønregulering\n", "field campain not found"},
+		{"unknown nested key", "version: 1\nrender:\n  limt: 5\n", "field limt not found"},
+		{"holiday date", "version: 1\ndata:\n  effective_dates:\n    - {date: 18. april 2025, weight: 1}\n",
+			"data.effective_dates[0].date: 18. april 2025 is a public holiday (Langfredag)"},
+		{"date outside the campaign", "version: 1\ndata:\n  effective_dates:\n    - {date: 2024-03-01, weight: 1}\n",
+			"data.effective_dates[0].date: 2024-03-01 is outside the campaign"},
+		{"date after payout", "version: 1\ncampaign:\n  agreement_effective_date: 1. august 2025\n",
+			"campaign.agreement_effective_date: 2025-08-01 is outside the campaign"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "dsb.yaml")
+			if err := os.WriteFile(filename, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(filename)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load succeeded for a missing file")
+	}
+}
+
+func TestLoadFollowsYear(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "dsb.yaml")
+	yaml := "version: 1\ncampaign:\n  year: 2026\n"
+	if err := os.WriteFile(filename, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(filename)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Campaign.Name != "Lønregulering 2026" {
+		t.Errorf("campaign name %q, want Lønregulering 2026", cfg.Campaign.Name)
+	}
+	for _, a := range cfg.Agreements {
+		if !strings.HasPrefix(a.EffectiveDate, "2026-") {
+			t.Errorf("agreement %s effective %s, want a date in 2026", a.Code, a.EffectiveDate)
+		}
+	}
+	for _, r := range cfg.Data.EffectiveDates {
+		if !strings.HasSuffix(r.Date, " 2026") {
+			t.Errorf("effective date rule %s, want a date in 2026", r.Date)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(c *Config)
+		want   []string
+	}{
+		{"default", func(c *Config) {}, nil},
+		{"several problems", func(c *Config) {
+			c.Version = 0
+			c.Workers = 0
+			c.Render.Limit = -1
+		}, []string{"version: is required", "render.limit: must not be negative", "workers: must be between 1 and 64"}},
+		{"budget without amount or percent", func(c *Config) {
+			c.Budgets = []BudgetConfig{{Department: "IT"}}
+		}, []string{"budgets[0]: needs exactly one of"}},
+		{"agreement in another year", func(c *Config) {
+			c.Agreements[0].EffectiveDate = "2024-05-01"
+		}, []string{"agreements[0].effective_date: 2024-05-01 is outside the campaign"}},
+		{"effective window past payout", func(c *Config) {
+			c.Data.EffectiveDates = []DateRuleConfig{{From: "2025-03-01", To: "2025-09-01", Weight: 1}}
+		}, []string{"data.effective_dates[0].to: 2025-09-01 is outside the campaign"}},
+		{"unknown filter header", func(c *Config) {
+			c.Render.Filter = `Departmnet == "IT"`
+		}, []string{`render.filter: unknown header "Departmnet"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.change(&cfg)
+			err := cfg.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("got error %v, want a *ValidationError", err)
+			}
+			if len(verr.Problems) != len(tt.want) {
+				t.Fatalf("got %d problems, want %d:\n%v", len(verr.Problems), len(tt.want), err)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(verr.Problems[i], want) {
+					t.Errorf("problem %d: got %q, want %q", i, verr.Problems[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"dsb-excel-generator/pkg/calendar"
+	"dsb-excel-generator/pkg/excel"
+	"dsb-excel-generator/pkg/filter"
+	"dsb-excel-generator/pkg/models"
+	"dsb-excel-generator/pkg/pdf"
+)
+
+// ValidationError lists every problem found in a config, each prefixed
+// with the path of the offending key
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d problem(s):\n  %s", len(e.Problems), strings.Join(e.Problems, "\n  "))
+}
+
+var placeholderPattern = regexp.MustCompile(`\{([^}]*)\}`)
+
+// Validate checks the config and returns a *ValidationError listing all problems
+func (c Config) Validate() error {
+	var problems []string
+	add := func(path, format string, args ...interface{}) {
+		problems = append(problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if c.Version == 0 {
+		add("version", "is required (current version is %d)", Version)
+	} else if c.Version != Version {
+		add("version", "unsupported version %d (current version is %d)", c.Version, Version)
+	}
+
+	if strings.TrimSpace(c.Campaign.Name) == "" {
+		add("campaign.name", "must not be empty")
+	}
+	if c.Campaign.Year < 2000 || c.Campaign.Year > 2100 {
+		add("campaign.year", "must be between 2000 and 2100, got %d", c.Campaign.Year)
+	}
+	if strings.TrimSpace(c.Campaign.Agreement) == "" {
+		add("campaign.agreement", "must not be empty")
+	} else if !c.hasAgreement(c.Campaign.Agreement) {
+		add("campaign.agreement", "unknown agreement %q (known: %s)", c.Campaign.Agreement, strings.Join(c.agreementCodes(), ", "))
+	}
+	payoutMonth, err := models.ParseDanishMonth(c.Campaign.PayoutMonth)
+	if err != nil {
+		add("campaign.payout_month", "%v; use januar to december", err)
+	}
+	// Agreement and effective dates fall in the campaign year, no later than the payout month
+	checkCampaignDate := func(path string, d models.Date) {
+		if payoutMonth == 0 || d.IsZero() {
+			return
+		}
+		if d.Year() != c.Campaign.Year || d.Month() > payoutMonth {
+			add(path, "%s is outside the campaign: must be in januar to %s %d", d, models.DanishMonths[payoutMonth-1], c.Campaign.Year)
+		}
+	}
+	if override, err := parseOptionalDate(c.Campaign.AgreementEffectiveDate); err != nil {
+		add("campaign.agreement_effective_date", "%v", err)
+	} else {
+		checkCampaignDate("campaign.agreement_effective_date", override)
+	}
+	if c.Workbook == "" {
+		add("workbook", "must not be empty")
+	}
+
+	// Agreements
+	if len(c.Agreements) == 0 {
+		add("agreements", "must list at least one agreement")
+	}
+	seenAgreements := make(map[string]bool)
+	for i, a := range c.Agreements {
+		path := fmt.Sprintf("agreements[%d]", i)
+		if strings.TrimSpace(a.Code) == "" {
+			add(path+".code", "must not be empty")
+		} else if seenAgreements[a.Code] {
+			add(path+".code", "%q is listed more than once", a.Code)
+		}
+		seenAgreements[a.Code] = true
+		if strings.TrimSpace(a.Name) == "" {
+			add(path+".name", "must not be empty")
+		}
+		if effective, err := models.ParseDate(a.EffectiveDate); err != nil {
+			add(path+".effective_date", "%v", err)
+		} else {
+			checkCampaignDate(path+".effective_date", effective)
+		}
+		percentages := []struct {
+			name  string
+			value float64
+		}{
+			{"general_increase", a.GeneralIncrease}, {"pension_increase", a.PensionIncrease},
+			{"employee_pension_rate", a.EmployeePensionRate}, {"employer_pension_rate", a.EmployerPensionRate},
+		}
+		for _, p := range percentages {
+			if p.value < 0 || p.value > 30 {
+				add(path+"."+p.name, "must be between 0 and 30 percent, got %g", p.value)
+			}
+		}
+	}
+
+	// Budgets
+	seenBudgets := make(map[string]bool)
+	for i, b := range c.Budgets {
+		path := fmt.Sprintf("budgets[%d]", i)
+		if !excel.IsDepartment(b.Department) {
+			add(path+".department", "unknown department %q", b.Department)
+		} else if seenBudgets[b.Department] {
+			add(path+".department", "%q is listed more than once", b.Department)
+		}
+		seenBudgets[b.Department] = true
+		if b.Amount < 0 {
+			add(path+".amount", "must not be negative, got %g", b.Amount)
+		}
+		if b.Percent < 0 || b.Percent > 30 {
+			add(path+".percent", "must be between 0 and 30 percent, got %g", b.Percent)
+		}
+		if (b.Amount > 0) == (b.Percent > 0) {
+			add(path, "needs exactly one of a positive amount or a positive percent")
+		}
+	}
+	if c.Data.AllocateBudgets && len(c.Budgets) == 0 {
+		add("data.allocate_budgets", "needs budgets to allocate within")
+	}
+
+	// Data generation
+	if c.Data.Rows <= 0 {
+		add("data.rows", "must be positive, got %d", c.Data.Rows)
+	}
+	if c.Data.HighlightIncrease <= 0 || c.Data.HighlightIncrease > 100 {
+		add("data.highlight_increase", "must be above 0 and at most 100 percent, got %g", c.Data.HighlightIncrease)
+	}
+	if len(c.Data.EffectiveDates) == 0 {
+		add("data.effective_dates", "must list at least one date")
+	}
+	for i, r := range c.Data.EffectiveDates {
+		path := fmt.Sprintf("data.effective_dates[%d]", i)
+		valid := true
+		for _, field := range []struct{ name, value string }{{"date", r.Date}, {"from", r.From}, {"to", r.To}} {
+			if d, err := parseOptionalDate(field.value); err != nil {
+				add(path+"."+field.name, "%v", err)
+				valid = false
+			} else {
+				checkCampaignDate(path+"."+field.name, d)
+			}
+		}
+		switch {
+		case r.Date != "" && (r.From != "" || r.To != ""):
+			add(path, "use either date or from/to, not both")
+		case r.Date == "" && (r.From == "" || r.To == ""):
+			add(path, "needs a date, or both from and to")
+		case r.Date != "" && valid:
+			if name, ok := calendar.HolidayName(r.Rule().Date.Time); ok {
+				add(path+".date", "%s is a public holiday (%s)", r.Date, name)
+			}
+		case r.Date == "" && valid:
+			rule := r.Rule()
+			if rule.To.Before(rule.From) {
+				add(path+".to", "%s is before from %s", rule.To, rule.From)
+			} else if len(rule.Candidates()) == 0 {
+				add(path, "no first of month between %s and %s", rule.From, rule.To)
+			}
+		}
+		if r.Weight <= 0 {
+			add(path+".weight", "must be positive, got %d", r.Weight)
+		}
+	}
+	if len(c.Data.Templates) == 0 {
+		add("data.templates", "must list at least one letter type")
+	}
+	seenTemplates := make(map[string]bool)
+	for i, t := range c.Data.Templates {
+		path := fmt.Sprintf("data.templates[%d]", i)
+		if !excel.IsLetterType(t.LetterType) {
+			add(path+".letter_type", "unknown letter type %q (known: %s)", t.LetterType, strings.Join(excel.LetterTypes, ", "))
+		} else if seenTemplates[t.LetterType] {
+			add(path+".letter_type", "%q is listed more than once", t.LetterType)
+		}
+		seenTemplates[t.LetterType] = true
+		if t.Weight <= 0 {
+			add(path+".weight", "must be positive, got %d", t.Weight)
+		}
+	}
+
+	if len(c.Data.Agreements) == 0 {
+		add("data.agreements", "must list at least one agreement")
+	}
+	seenWeights := make(map[string]bool)
+	for i, a := range c.Data.Agreements {
+		path := fmt.Sprintf("data.agreements[%d]", i)
+		if !c.hasAgreement(a.Agreement) {
+			add(path+".agreement", "unknown agreement %q (known: %s)", a.Agreement, strings.Join(c.agreementCodes(), ", "))
+		} else if seenWeights[a.Agreement] {
+			add(path+".agreement", "%q is listed more than once", a.Agreement)
+		}
+		seenWeights[a.Agreement] = true
+		if a.Weight <= 0 {
+			add(path+".weight", "must be positive, got %d", a.Weight)
+		}
+	}
+
+	// Rendering
+	if c.Render.OutputDir == "" {
+		add("render.output_dir", "must not be empty")
+	}
+	if c.Render.Limit < 0 {
+		add("render.limit", "must not be negative, got %d", c.Render.Limit)
+	}
+	if c.Render.Sample < 0 {
+		add("render.sample", "must not be negative, got %d", c.Render.Sample)
+	}
+	if c.Render.Filter != "" {
+		expr, err := filter.Parse(c.Render.Filter)
+		if err != nil {
+			add("render.filter", "%v", err)
+		} else {
+			for _, name := range expr.Fields() {
+				if !isHeader(name) {
+					add("render.filter", "unknown header %q", name)
+				}
+			}
+		}
+	}
+	if c.Workers < 1 || c.Workers > 64 {
+		add("workers", "must be between 1 and 64, got %d", c.Workers)
+	}
+
+	// Print batch
+	if c.Print.OutputDir == "" {
+		add("print.output_dir", "must not be empty")
+	}
+	if c.Reports.OutputDir == "" {
+		add("reports.output_dir", "must not be empty")
+	}
+
+	// Pre-flight check
+	if c.Preflight.MinIncrease > c.Preflight.MaxIncrease {
+		add("preflight.min_increase", "must not exceed max_increase (%g), got %g", c.Preflight.MaxIncrease, c.Preflight.MinIncrease)
+	}
+	if c.Preflight.GrossRatioTolerance <= 0 {
+		add("preflight.gross_ratio_tolerance", "must be positive, got %g", c.Preflight.GrossRatioTolerance)
+	}
+	if c.Print.LettersPerFile < 0 {
+		add("print.letters_per_file", "must not be negative, got %d", c.Print.LettersPerFile)
+	}
+	if omr := c.Print.OMR; omr.Enabled {
+		sizes := []struct {
+			name  string
+			value float64
+		}{
+			{"left", omr.Left}, {"top", omr.Top}, {"pitch", omr.Pitch}, {"length", omr.Length}, {"thickness", omr.Thickness},
+		}
+		for _, size := range sizes {
+			if size.value <= 0 {
+				add("print.omr."+size.name, "must be positive when OMR marks are enabled, got %g", size.value)
+			}
+		}
+		if omr.SequenceBits < 0 || omr.SequenceBits > 3 {
+			add("print.omr.sequence_bits", "must be between 0 and 3, got %d", omr.SequenceBits)
+		}
+	}
+
+	// Output naming
+	if !strings.HasSuffix(strings.ToLower(c.Output.FileName), ".pdf") {
+		add("output.file_name", "%q must end in .pdf", c.Output.FileName)
+	}
+	unique := false
+	for _, m := range placeholderPattern.FindAllStringSubmatch(c.Output.FileName, -1) {
+		switch name := m[1]; {
+		case name == "campaign" || name == "year":
+		case name == "CPR" || name == "EmployeeNumber":
+			unique = true
+		case !isHeader(name):
+			add("output.file_name", "unknown placeholder {%s}; use {campaign}, {year} or a column header", name)
+		}
+	}
+	if !unique {
+		add("output.file_name", "must contain {CPR} or {EmployeeNumber} so every letter gets its own file")
+	}
+
+	// Security
+	for i, level := range c.Security.ProtectLevels {
+		if !contains(excel.SecurityLevels, level) {
+			add(fmt.Sprintf("security.protect_levels[%d]", i), "unknown security level %q (known: %s)", level, strings.Join(excel.SecurityLevels, ", "))
+		}
+	}
+	switch c.Security.UserPassword {
+	case pdf.UserPasswordNone, pdf.UserPasswordBirthdate:
+	default:
+		add("security.user_password", "must be %q or %q, got %q", pdf.UserPasswordNone, pdf.UserPasswordBirthdate, c.Security.UserPassword)
+	}
+	if len(c.Security.ProtectLevels) > 0 && c.Security.OwnerPasswordEnv == "" {
+		add("security.owner_password_env", "must name an environment variable when protect_levels is set")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// agreementCodes returns the codes of the configured agreements
+func (c Config) agreementCodes() []string {
+	codes := make([]string, 0, len(c.Agreements))
+	for _, a := range c.Agreements {
+		codes = append(codes, a.Code)
+	}
+	return codes
+}
+
+// hasAgreement reports whether an agreement with the code is configured
+func (c Config) hasAgreement(code string) bool {
+	return contains(c.agreementCodes(), code)
+}
+
+func isHeader(name string) bool {
+	return excel.IsHeader(name)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckSecrets checks that the secrets needed for rendering protected letters
+// are available in the environment
+func (c Config) CheckSecrets() error {
+	if len(c.Security.ProtectLevels) > 0 && os.Getenv(c.Security.OwnerPasswordEnv) == "" {
+		return fmt.Errorf("security.owner_password_env: environment variable %s is not set", c.Security.OwnerPasswordEnv)
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+// Package equity analyses pay equity between women and men, with the gender
+// taken from the CPR number. Salaries are compared at full time, so part-time
+// and hourly employees are compared on equal terms.
+package equity
+
+import (
+	"fmt"
+	"sort"
+
+	"dsb-excel-generator/pkg/models"
+)
+
+// Dimensions the employees are grouped by. All has a single group.
+const (
+	All        = "All"
+	Department = "Department"
+	Agreement  = "Agreement"
+	AgeBand    = "AgeBand"
+)
+
+// Dimensions lists the dimensions in report order
+var Dimensions = []string{All, Department, Agreement, AgeBand}
+
+// MinGroupSize is the smallest number of women or men whose statistics are
+// reported, so individual salaries cannot be derived from the report
+const MinGroupSize = 5
+
+// Stats summarises the salaries and increases of the women or the men of a group
+type Stats struct {
+	Count int
+	// Full-time base salaries before and after the regulation
+	MeanSalary      float64
+	MedianSalary    float64
+	MeanNewSalary   float64
+	MedianNewSalary float64
+	// Total increases in percent
+	MeanIncrease   float64
+	MedianIncrease float64
+}
+
+// Reported reports whether the statistics cover enough employees to be shown
+func (s Stats) Reported() bool {
+	return s.Count >= MinGroupSize
+}
+
+// Group compares the women and men of a group, e.g. a department
+type Group struct {
+	Dimension string
+	Name      string
+	Women     Stats
+	Men       Stats
+}
+
+// Comparable reports whether both women and men are reported
+func (g Group) Comparable() bool {
+	return g.Women.Reported() && g.Men.Reported()
+}
+
+// MeanGap returns how much lower women's mean salary after the regulation is
+// than men's, in percent of men's; negative if women's is higher
+func (g Group) MeanGap() float64 {
+	return gap(g.Women.MeanNewSalary, g.Men.MeanNewSalary)
+}
+
+// MedianGap is MeanGap for the medians
+func (g Group) MedianGap() float64 {
+	return gap(g.Women.MedianNewSalary, g.Men.MedianNewSalary)
+}
+
+// MeanGapBefore is MeanGap for the salaries before the regulation
+func (g Group) MeanGapBefore() float64 {
+	return gap(g.Women.MeanSalary, g.Men.MeanSalary)
+}
+
+// Report is the result of a pay-equity analysis
+type Report struct {
+	// On is the date ages are calculated on
+	On     models.Date
+	Groups []Group
+	// AdjustedGap and AdjustedGapBefore compare women and men with the same
+	// department, agreement, age band and role: the mean gap of every such
+	// cell with both women and men, weighted by the cell's size
+	AdjustedGap       float64
+	AdjustedGapBefore float64
+	// Covered is the number of employees in cells with both women and men
+	Covered int
+	// Employees is the number of employees analysed
+	Employees int
+	// Skipped lists the employee numbers of rows without a valid CPR number
+	Skipped []string
+}
+
+// person is an employee as analysed
+type person struct {
+	gender    string
+	groups    map[string]string
+	cell      string
+	salary    float64
+	newSalary float64
+	increase  float64
+}
+
+// Analyze compares the salaries of women and men in total and by department,
+// agreement and age band on the given date
+func Analyze(employees []models.EmployeeData, on models.Date) Report {
+	report := Report{On: on}
+	var people []person
+	for _, emp := range employees {
+		gender, err := models.CPRGender(emp.CPR)
+		birth, birthErr := models.CPRBirthDate(emp.CPR)
+		if err != nil || birthErr != nil {
+			report.Skipped = append(report.Skipped, emp.EmployeeNumber)
+			continue
+		}
+		band := Band(models.AgeOn(birth, on))
+		people = append(people, person{
+			gender: gender,
+			groups: map[string]string{
+				All:        "All employees",
+				Department: emp.Department,
+				Agreement:  emp.Agreement,
+				AgeBand:    band,
+			},
+			cell:      fmt.Sprintf("%s|%s|%s|%s", emp.Department, emp.Agreement, band, emp.Role),
+			salary:    emp.Amount("FullTimeBaseSalary"),
+			newSalary: emp.Amount("NewFullTimeBaseSalary"),
+			increase:  emp.TotalIncrease(),
+		})
+	}
+	report.Employees = len(people)
+
+	for _, dimension := range Dimensions {
+		members := make(map[string][]person)
+		for _, p := range people {
+			members[p.groups[dimension]] = append(members[p.groups[dimension]], p)
+		}
+		names := make([]string, 0, len(members))
+		for name := range members {
+			names = append(names, name)
+		}
+		// Age bands sort by age, everything else by name
+		if dimension == AgeBand {
+			sort.Slice(names, func(i, j int) bool { return bandOrder(names[i]) < bandOrder(names[j]) })
+		} else {
+			sort.Strings(names)
+		}
+		for _, name := range names {
+			report.Groups = append(report.Groups, group(dimension, name, members[name]))
+		}
+	}
+
+	report.AdjustedGap, report.AdjustedGapBefore, report.Covered = adjustedGap(people)
+	return report
+}
+
+// group summarises the women and men of a group
+func group(dimension, name string, people []person) Group {
+	var women, men []person
+	for _, p := range people {
+		if p.gender == models.GenderFemale {
+			women = append(women, p)
+		} else {
+			men = append(men, p)
+		}
+	}
+	return Group{Dimension: dimension, Name: name, Women: stats(women), Men: stats(men)}
+}
+
+// stats summarises salaries and increases
+func stats(people []person) Stats {
+	var salaries, newSalaries, increases []float64
+	for _, p := range people {
+		salaries = append(salaries, p.salary)
+		newSalaries = append(newSalaries, p.newSalary)
+		increases = append(increases, p.increase)
+	}
+	return Stats{
+		Count:           len(people),
+		MeanSalary:      mean(salaries),
+		MedianSalary:    median(salaries),
+		MeanNewSalary:   mean(newSalaries),
+		MedianNewSalary: median(newSalaries),
+		MeanIncrease:    mean(increases),
+		MedianIncrease:  median(increases),
+	}
+}
+
+// adjustedGap returns the mean gap after and before the regulation within
+// cells of comparable employees, weighted by cell size, and the number of
+// employees in cells with both women and men
+func adjustedGap(people []person) (after, before float64, covered int) {
+	cells := make(map[string][]person)
+	for _, p := range people {
+		cells[p.cell] = append(cells[p.cell], p)
+	}
+	// Summed in a fixed order, so the result does not depend on map order
+	keys := make([]string, 0, len(cells))
+	for key := range cells {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		members := cells[key]
+		g := group("", "", members)
+		if g.Women.Count == 0 || g.Men.Count == 0 {
+			continue
+		}
+		n := float64(len(members))
+		after += g.MeanGap() * n
+		before += g.MeanGapBefore() * n
+		covered += len(members)
+	}
+	if covered == 0 {
+		return 0, 0, 0
+	}
+	return after / float64(covered), before / float64(covered), covered
+}
+
+// Age bands in order
+var bands = []struct {
+	Name   string
+	MaxAge int
+}{
+	{"Under 30", 29},
+	{"30-39", 39},
+	{"40-49", 49},
+	{"50-59", 59},
+	{"60+", 200},
+}
+
+// Band returns the age band of an age, e.g. "30-39"
+func Band(age int) string {
+	for _, b := range bands {
+		if age <= b.MaxAge {
+			return b.Name
+		}
+	}
+	return bands[len(bands)-1].Name
+}
+
+// bandOrder returns the position of an age band
+func bandOrder(name string) int {
+	for i, b := range bands {
+		if b.Name == name {
+			return i
+		}
+	}
+	return len(bands)
+}
+
+// gap returns how much lower women's amount is than men's, in percent of men's
+func gap(women, men float64) float64 {
+	if men == 0 {
+		return 0
+	}
+	return (men - women) / men * 100
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,100 @@
+package equity
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"dsb-excel-generator/pkg/models"
+)
+
+// employee returns an employee with full-time salaries before and after
+func employee(number, cpr, department, role string, salary, newSalary float64) models.EmployeeData {
+	return models.EmployeeData{
+		EmployeeNumber:        number,
+		CPR:                   cpr,
+		Department:            department,
+		Agreement:             "HK",
+		Role:                  role,
+		BaseSalary:            fmt.Sprintf("%.2f", salary),
+		NewBaseSalary:         fmt.Sprintf("%.2f", newSalary),
+		FullTimeBaseSalary:    fmt.Sprintf("%.2f", salary),
+		NewFullTimeBaseSalary: fmt.Sprintf("%.2f", newSalary),
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	// Even last CPR digits are women, odd are men
+	employees := []models.EmployeeData{
+		// Developers in IT aged 30-39: women earn 20% less before, 19.61% after
+		employee("1", "010190-1234", "IT", "Developer", 40000, 41000),
+		employee("2", "020290-1235", "IT", "Developer", 50000, 51000),
+		// Advisors in HR aged 30-39: 9.09% before, 8.20% after
+		employee("3", "030390-1236", "HR", "Advisor", 30000, 30900),
+		employee("4", "040490-1237", "HR", "Advisor", 30000, 30600),
+		employee("5", "050590-1239", "HR", "Advisor", 36000, 36720),
+		// A woman under 30 without men to compare with
+		employee("6", "010100-4002", "IT", "Developer", 35000, 36050),
+		// Not a valid CPR number
+		employee("7", "31049-1234", "IT", "Developer", 35000, 36050),
+	}
+	report := Analyze(employees, models.NewDate(2025, time.June, 1))
+
+	if report.Employees != 6 || fmt.Sprint(report.Skipped) != "[7]" {
+		t.Errorf("analysed %d employees, skipped %v, want 6 and [7]", report.Employees, report.Skipped)
+	}
+	if report.Covered != 5 {
+		t.Errorf("covered %d employees, want 5", report.Covered)
+	}
+
+	near := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 0.001 {
+			t.Errorf("%s = %.4f, want %.4f", name, got, want)
+		}
+	}
+	// Cells weighted by size: (2*20 + 3*9.0909) / 5 and (2*19.6078 + 3*8.1996) / 5
+	near("AdjustedGapBefore", report.AdjustedGapBefore, 13.4545)
+	near("AdjustedGap", report.AdjustedGap, 12.7629)
+
+	all := report.Groups[0]
+	if all.Dimension != All || all.Women.Count != 3 || all.Men.Count != 3 {
+		t.Fatalf("first group %s %q with %d women and %d men, want All with 3 and 3",
+			all.Dimension, all.Name, all.Women.Count, all.Men.Count)
+	}
+	// Women average 35000 before and 35983.33 after, men 38666.67 and 39440
+	near("MeanGapBefore", all.MeanGapBefore(), 9.4828)
+	near("MeanGap", all.MeanGap(), 8.7644)
+	// Medians: women 35000 and 36050, men 36000 and 36720
+	near("MedianGap", all.MedianGap(), 1.8246)
+
+	var bands []string
+	for _, g := range report.Groups {
+		if g.Dimension == AgeBand {
+			bands = append(bands, g.Name)
+		}
+	}
+	if got := fmt.Sprint(bands); got != "[Under 30 30-39]" {
+		t.Errorf("age bands %s, want [Under 30 30-39]", got)
+	}
+}
+
+func TestBand(t *testing.T) {
+	tests := []struct {
+		age  int
+		want string
+	}{
+		{18, "Under 30"},
+		{29, "Under 30"},
+		{30, "30-39"},
+		{49, "40-49"},
+		{59, "50-59"},
+		{60, "60+"},
+	}
+	for _, tt := range tests {
+		if got := Band(tt.age); got != tt.want {
+			t.Errorf("Band(%d) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
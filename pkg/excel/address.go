@@ -0,0 +1,52 @@
+package excel
+
+import "fmt"
+
+// Danish street names
+var danishStreets = []string{
+	"Vestergade", "Østergade", "Nørregade", "Søndergade", "Algade", "Skolevej",
+	"Kirkevej", "Møllevej", "Stationsvej", "Parkvej", "Birkevej", "Egevej",
+	"Bøgevej", "Engvej", "Skovvej", "Strandvejen", "Jernbanegade", "Havnegade",
+	"Rosenvænget", "Lindevej", "Ahornvej", "Elmevej", "Kastanievej", "Torvegade",
+	"Bredgade", "Klostergade", "Fælledvej", "Bakkevej", "Åboulevarden", "Vesterbrogade",
+}
+
+// postalDistrict pairs a Danish postcode with its city
+type postalDistrict struct {
+	PostCode string
+	City     string
+}
+
+// Danish postal districts
+var postalDistricts = []postalDistrict{
+	{"1050", "København K"}, {"1620", "København V"}, {"2100", "København Ø"},
+	{"2200", "København N"}, {"2300", "København S"}, {"2400", "København NV"},
+	{"2500", "Valby"}, {"2600", "Glostrup"}, {"2630", "Taastrup"},
+	{"2800", "Kongens Lyngby"}, {"3000", "Helsingør"}, {"3400", "Hillerød"},
+	{"3700", "Rønne"}, {"4000", "Roskilde"}, {"4200", "Slagelse"},
+	{"4700", "Næstved"}, {"4800", "Nykøbing F"}, {"5000", "Odense C"},
+	{"6000", "Kolding"}, {"6400", "Sønderborg"}, {"6700", "Esbjerg"},
+	{"7100", "Vejle"}, {"7400", "Herning"}, {"8000", "Aarhus C"},
+	{"8600", "Silkeborg"}, {"8700", "Horsens"}, {"8900", "Randers C"},
+	{"9000", "Aalborg"},
+}
+
+// Floor and door designations used for apartments
+var apartmentSuffixes = []string{"st. th.", "st. tv.", "1. th.", "1. tv.", "2. th.", "2. tv.", "3. mf."}
+
+// generateAddress generates a synthetic Danish postal address
+func generateAddress() (street, houseNumber, postCode, city string) {
+	street = danishStreets[rng.Intn(len(danishStreets))]
+
+	houseNumber = fmt.Sprintf("%d", rng.Intn(150)+1)
+	if rng.Float64() < 0.1 {
+		houseNumber += string(rune('A' + rng.Intn(3)))
+	}
+	// Roughly a third live in apartments
+	if rng.Float64() < 0.35 {
+		houseNumber += ", " + apartmentSuffixes[rng.Intn(len(apartmentSuffixes))]
+	}
+
+	district := postalDistricts[rng.Intn(len(postalDistricts))]
+	return street, houseNumber, district.PostCode, district.City
+}
@@ -0,0 +1,105 @@
+package excel
+
+import (
+	"fmt"
+	"math"
+
+	"dsb-excel-generator/pkg/models"
+)
+
+// Budget is a department's monthly pool for individual adjustments, either
+// an amount in kroner or a percentage of the department's payroll (the sum
+// of its base salaries before the regulation)
+type Budget struct {
+	Department string
+	Amount     float64
+	Percent    float64
+}
+
+// Pool returns the budget in kroner for the department's payroll
+func (b Budget) Pool(payroll float64) float64 {
+	if b.Percent > 0 {
+		return round2(payroll * b.Percent / 100)
+	}
+	return b.Amount
+}
+
+// BudgetUse is the individual adjustments of a department against its pool
+type BudgetUse struct {
+	Department string
+	Employees  int
+	// Payroll is the sum of the department's base salaries
+	Payroll float64
+	Pool    float64
+	// Used is the sum of the department's individual adjustments
+	Used float64
+}
+
+// Remaining returns the part of the pool not used; negative for an overrun
+func (u BudgetUse) Remaining() float64 {
+	return round2(u.Pool - u.Used)
+}
+
+// Over reports whether the adjustments exceed the pool
+func (u BudgetUse) Over() bool {
+	return u.Remaining() < 0
+}
+
+// CheckBudgets sums the individual adjustments of each budgeted department
+// and compares them with its pool, in the order of the budgets
+func CheckBudgets(employees []models.EmployeeData, budgets []Budget) []BudgetUse {
+	byDepartment := make(map[string]*BudgetUse)
+	uses := make([]BudgetUse, len(budgets))
+	for i, b := range budgets {
+		uses[i].Department = b.Department
+		byDepartment[b.Department] = &uses[i]
+	}
+	for _, emp := range employees {
+		if u, ok := byDepartment[emp.Department]; ok {
+			u.Employees++
+			u.Payroll += emp.Amount("BaseSalary")
+			u.Used += emp.Amount("IndividualAdjustment")
+		}
+	}
+	for i, b := range budgets {
+		uses[i].Payroll = round2(uses[i].Payroll)
+		uses[i].Used = round2(uses[i].Used)
+		uses[i].Pool = b.Pool(uses[i].Payroll)
+	}
+	return uses
+}
+
+// checkBudgets checks that budgets name generated departments once and have
+// exactly one of a positive amount or percentage
+func checkBudgets(budgets []Budget) error {
+	seen := make(map[string]bool)
+	for _, b := range budgets {
+		if !IsDepartment(b.Department) {
+			return fmt.Errorf("budget for unknown department %q", b.Department)
+		}
+		if seen[b.Department] {
+			return fmt.Errorf("more than one budget for department %s", b.Department)
+		}
+		seen[b.Department] = true
+		if b.Amount < 0 || b.Percent < 0 || (b.Amount > 0) == (b.Percent > 0) {
+			return fmt.Errorf("budget of department %s needs exactly one of a positive amount or a positive percentage", b.Department)
+		}
+	}
+	return nil
+}
+
+// budgetScales returns, for each department over its pool, the factor that
+// scales its individual adjustment percentages down into the pool
+func budgetScales(employees []models.EmployeeData, budgets []Budget) map[string]float64 {
+	scales := make(map[string]float64)
+	for _, u := range CheckBudgets(employees, budgets) {
+		if !u.Over() {
+			continue
+		}
+		// Adjustments are rounded to the nearest øre both before and after
+		// scaling, which can add up to an øre per employee
+		pool := u.Pool - 0.01*float64(u.Employees)
+		scales[u.Department] = math.Max(pool, 0) / u.Used
+	}
+	return scales
+}
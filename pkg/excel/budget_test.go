@@ -0,0 +1,82 @@
+package excel
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBudgetDefinitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		budgets []Budget
+		want    string
+	}{
+		{"amount", []Budget{{Department: "IT", Amount: 25000}}, ""},
+		{"percent", []Budget{{Department: "IT", Percent: 2}}, ""},
+		{"neither", []Budget{{Department: "IT"}}, "exactly one of"},
+		{"both", []Budget{{Department: "IT", Amount: 25000, Percent: 2}}, "exactly one of"},
+		{"negative amount", []Budget{{Department: "IT", Amount: -1}}, "exactly one of"},
+		{"negative percent with amount", []Budget{{Department: "IT", Amount: 25000, Percent: -1}}, "exactly one of"},
+		{"unknown department", []Budget{{Department: "Research", Percent: 2}}, "unknown department"},
+		{"twice", []Budget{{Department: "IT", Percent: 2}, {Department: "IT", Amount: 100}}, "more than one budget"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBudgets(tt.budgets)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocateBudgets(t *testing.T) {
+	budgets := []Budget{
+		{Department: "IT", Percent: 2.5},
+		{Department: "Finance", Percent: 2.5},
+		{Department: "Customer Service", Percent: 2},
+		{Department: "Legal", Amount: 25000},
+	}
+	tests := []struct {
+		name     string
+		seed     int64
+		rows     int
+		formulas bool
+	}{
+		{"seed 1", 1, 3000, false},
+		{"seed 2", 2, 3000, false},
+		{"seed 3", 3, 3000, false},
+		{"seed 42", 42, 1000, false},
+		{"formulas", 2, 500, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.Rows = tt.rows
+			opts.Seed = tt.seed
+			opts.Budgets = budgets
+			opts.AllocateBudgets = true
+			opts.Formulas = tt.formulas
+			filename := filepath.Join(t.TempDir(), "budgets.xlsx")
+			if err := Generate(filename, opts); err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			employees, err := ReadEmployees(filename)
+			if err != nil {
+				t.Fatalf("ReadEmployees: %v", err)
+			}
+			for _, u := range CheckBudgets(employees, budgets) {
+				if u.Over() {
+					t.Errorf("%s is over budget: pool %.2f, used %.2f", u.Department, u.Pool, u.Used)
+				}
+			}
+		})
+	}
+}
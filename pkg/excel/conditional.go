@@ -0,0 +1,108 @@
+package excel
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// DefaultHighlightIncrease is the individual increase, in percent, above
+// which PercentageIncrease is highlighted
+const DefaultHighlightIncrease = 4.0
+
+// Review colours. Black text keeps a contrast of at least 7:1 on every fill,
+// and each rule also changes the font, so no rule relies on colour alone.
+// Blue and amber stay distinguishable with red-green colour blindness.
+const (
+	scaleLowColor     = "#FFFFFF"
+	scaleHighColor    = "#9DC3E6" // light blue
+	highIncreaseColor = "#FFD966" // amber, bold
+	confidentialColor = "#D9D2E9" // lavender, italic
+	unreconciledColor = "#F4B183" // orange, bold and double underlined
+)
+
+// formatForReview adds conditional formatting to the employee sheet, in
+// order of priority:
+//   - rows whose NewBaseSalary or IndividualAdjustment does not reconcile
+//     with the other columns, within the øre Verify allows
+//   - PercentageIncrease above highlightIncrease
+//   - a colour scale over PercentageIncrease
+//   - Strictly Confidential rows
+//
+// The rules cover every row the generator can write, like the validation.
+func formatForReview(f *excelize.File, highlightIncrease float64) error {
+	style := func(s *excelize.Style) (*int, error) {
+		id, err := f.NewConditionalStyle(s)
+		if err != nil {
+			return nil, fmt.Errorf("error creating conditional style: %v", err)
+		}
+		return &id, nil
+	}
+	fill := func(color string) excelize.Fill {
+		return excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{color}}
+	}
+	unreconciled, err := style(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Underline: "double", Color: "#000000"},
+		Fill: fill(unreconciledColor),
+	})
+	if err != nil {
+		return err
+	}
+	highIncrease, err := style(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "#000000"},
+		Fill: fill(highIncreaseColor),
+	})
+	if err != nil {
+		return err
+	}
+	confidential, err := style(&excelize.Style{
+		Font: &excelize.Font{Italic: true, Color: "#000000"},
+		Fill: fill(confidentialColor),
+	})
+	if err != nil {
+		return err
+	}
+
+	// References to the first data row; formulas are relative to the top
+	// left cell of their range
+	cell := func(header string) string {
+		return fmt.Sprintf("$%s2", getExcelColumn(headerIndex(header)))
+	}
+	columnRange := func(header string) string {
+		col := getExcelColumn(headerIndex(header))
+		return fmt.Sprintf("%s2:%s%d", col, col, maxRows+1)
+	}
+	rowRange := fmt.Sprintf("A2:%s%d", getExcelColumn(len(Headers)-1), maxRows+1)
+
+	base, newBase := cell("BaseSalary"), cell("NewBaseSalary")
+	general, individual, percentage := cell("GeneralAdjustment"), cell("IndividualAdjustment"), cell("PercentageIncrease")
+	rules := []struct {
+		Range  string
+		Format excelize.ConditionalFormatOptions
+	}{
+		{rowRange, excelize.ConditionalFormatOptions{
+			Type: "formula",
+			Criteria: fmt.Sprintf("OR(ABS(%s+%s+%s-%s)>0.02,AND(%s>0,ABS(%s/%s*100-%s)>0.01))",
+				base, general, individual, newBase, base, individual, base, percentage),
+			Format: unreconciled,
+		}},
+		{columnRange("PercentageIncrease"), excelize.ConditionalFormatOptions{
+			Type: "cell", Criteria: ">", Value: fmt.Sprintf("%g", highlightIncrease), Format: highIncrease,
+		}},
+		{columnRange("PercentageIncrease"), excelize.ConditionalFormatOptions{
+			Type: "2_color_scale", Criteria: "=",
+			MinType: "min", MaxType: "max", MinColor: scaleLowColor, MaxColor: scaleHighColor,
+		}},
+		{rowRange, excelize.ConditionalFormatOptions{
+			Type:     "formula",
+			Criteria: fmt.Sprintf(`%s="Strictly Confidential"`, cell("SecurityLevel")),
+			Format:   confidential,
+		}},
+	}
+	for _, r := range rules {
+		if err := f.SetConditionalFormat(SheetName, r.Range, []excelize.ConditionalFormatOptions{r.Format}); err != nil {
+			return fmt.Errorf("error adding conditional formatting to %s: %v", r.Range, err)
+		}
+	}
+	return nil
+}
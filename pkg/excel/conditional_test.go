@@ -0,0 +1,92 @@
+package excel
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readPart returns a part of a workbook file, e.g. "xl/workbook.xml"
+func readPart(t *testing.T, filename, name string) []byte {
+	t.Helper()
+	archive, err := zip.OpenReader(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	part, err := archive.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer part.Close()
+	data, err := io.ReadAll(part)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// conditionalRules reads the conditional formatting rules of the employee
+// sheet in order of priority, as "range type operator formula". excelize's
+// GetConditionalFormats keeps one rule per range, so the sheet XML is read.
+func conditionalRules(t *testing.T, filename string) []string {
+	t.Helper()
+	data := readPart(t, filename, "xl/worksheets/sheet1.xml")
+
+	var worksheet struct {
+		ConditionalFormatting []struct {
+			Sqref string `xml:"sqref,attr"`
+			Rules []struct {
+				Type     string `xml:"type,attr"`
+				Operator string `xml:"operator,attr"`
+				Priority int    `xml:"priority,attr"`
+				Formula  string `xml:"formula"`
+			} `xml:"cfRule"`
+		} `xml:"conditionalFormatting"`
+	}
+	if err := xml.Unmarshal(data, &worksheet); err != nil {
+		t.Fatal(err)
+	}
+	var rules []string
+	for _, cf := range worksheet.ConditionalFormatting {
+		for _, r := range cf.Rules {
+			rule := strings.Join(strings.Fields(fmt.Sprint(cf.Sqref, " ", r.Type, " ", r.Operator, " ", r.Formula)), " ")
+			if r.Priority != len(rules)+1 {
+				t.Errorf("rule %q has priority %d, want %d", rule, r.Priority, len(rules)+1)
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func TestFormatForReview(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Rows = 5
+	opts.Seed = 1
+	opts.HighlightIncrease = 3.5
+	filename := filepath.Join(t.TempDir(), "review.xlsx")
+	if err := Generate(filename, opts); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	col := func(header string) string { return getExcelColumn(headerIndex(header)) }
+	rows := fmt.Sprintf("A2:%s%d", col(Headers[len(Headers)-1]), maxRows+1)
+	increase := fmt.Sprintf("%s2:%s%d", col("PercentageIncrease"), col("PercentageIncrease"), maxRows+1)
+	want := []string{
+		fmt.Sprintf("%s expression OR(ABS($%s2+$%s2+$%s2-$%s2)>0.02,AND($%s2>0,ABS($%s2/$%s2*100-$%s2)>0.01))", rows,
+			col("BaseSalary"), col("GeneralAdjustment"), col("IndividualAdjustment"), col("NewBaseSalary"),
+			col("BaseSalary"), col("IndividualAdjustment"), col("BaseSalary"), col("PercentageIncrease")),
+		increase + " cellIs greaterThan 3.5",
+		increase + " colorScale",
+		fmt.Sprintf(`%s expression $%s2="Strictly Confidential"`, rows, col("SecurityLevel")),
+	}
+	got := conditionalRules(t, filename)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got rules\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,89 @@
+package excel
+
+import (
+	"fmt"
+	"time"
+
+	"dsb-excel-generator/pkg/calendar"
+	"dsb-excel-generator/pkg/models"
+)
+
+// DateRule is a rule for drawing effective dates. A rule either names a fixed
+// Date, or a window From–To from which the first day of a month is drawn
+// uniformly. A first of month that is a public holiday moves to the next
+// day that is not. Rules are chosen by relative Weight.
+type DateRule struct {
+	Date   models.Date
+	From   models.Date
+	To     models.Date
+	Weight int
+}
+
+// Candidates returns the dates the rule can produce
+func (r DateRule) Candidates() []models.Date {
+	if !r.Date.IsZero() {
+		return []models.Date{r.Date}
+	}
+
+	var dates []models.Date
+	for first := r.From.FirstOfMonth(); !first.After(r.To); first = models.DateOf(first.AddDate(0, 1, 0)) {
+		d := models.DateOf(calendar.NextNonHoliday(first.Time))
+		if !first.Before(r.From) && !d.After(r.To) {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// DefaultEffectiveDates returns the default effective date rules of a
+// campaign year: most changes take effect 1. marts, some on other dates.
+// A date that is a public holiday moves to the next day that is not.
+func DefaultEffectiveDates(year int) []DateRule {
+	rules := []DateRule{
+		{Date: models.NewDate(year, time.March, 1), Weight: 4},
+		{Date: models.NewDate(year, time.April, 1), Weight: 1},
+		{Date: models.NewDate(year, time.May, 1), Weight: 1},
+		{Date: models.NewDate(year, time.February, 1), Weight: 1},
+	}
+	for i := range rules {
+		rules[i].Date = models.DateOf(calendar.NextNonHoliday(rules[i].Date.Time))
+	}
+	return rules
+}
+
+// datePicker draws effective dates from a list of rules
+type datePicker struct {
+	weights    []int
+	candidates [][]models.Date
+}
+
+// newDatePicker checks the rules and prepares their candidate dates
+func newDatePicker(rules []DateRule) (*datePicker, error) {
+	p := &datePicker{}
+	for i, rule := range rules {
+		if rule.Weight <= 0 {
+			return nil, fmt.Errorf("effective date rule %d: weight must be positive, got %d", i+1, rule.Weight)
+		}
+		if !rule.Date.IsZero() {
+			if name, ok := calendar.HolidayName(rule.Date.Time); ok {
+				return nil, fmt.Errorf("effective date rule %d: %s is a public holiday (%s)", i+1, rule.Date, name)
+			}
+		}
+		candidates := rule.Candidates()
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("effective date rule %d: no first of month between %s and %s", i+1, rule.From, rule.To)
+		}
+		p.weights = append(p.weights, rule.Weight)
+		p.candidates = append(p.candidates, candidates)
+	}
+	if len(p.weights) == 0 {
+		return nil, fmt.Errorf("no effective date rules")
+	}
+	return p, nil
+}
+
+// pick draws a rule by weight and then one of its dates uniformly
+func (p *datePicker) pick() models.Date {
+	candidates := p.candidates[weightedIndex(p.weights)]
+	return candidates[rng.Intn(len(candidates))]
+}
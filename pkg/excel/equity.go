@@ -0,0 +1,114 @@
+package excel
+
+import (
+	"fmt"
+
+	"dsb-excel-generator/pkg/equity"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// EquitySheet is the worksheet of the pay-equity report
+const EquitySheet = "Pay Equity"
+
+// EquityHeaders lists the columns of the pay-equity report. Salaries are
+// full-time base salaries after the regulation, increases and gaps are in
+// percent, and gaps are positive where women earn less than men.
+var EquityHeaders = []string{
+	"Dimension", "Group", "Women", "Men",
+	"WomenMedianSalary", "MenMedianSalary", "MedianGap",
+	"WomenMeanSalary", "MenMeanSalary", "MeanGap", "MeanGapBefore",
+	"WomenMeanIncrease", "MenMeanIncrease", "WomenMedianIncrease", "MenMedianIncrease",
+}
+
+// WriteEquityReport writes a pay-equity analysis to a workbook. Statistics of
+// fewer than equity.MinGroupSize women or men are left empty.
+func WriteEquityReport(filename string, report equity.Report) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName(SheetName, EquitySheet); err != nil {
+		return fmt.Errorf("error naming sheet: %v", err)
+	}
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("error creating header style: %v", err)
+	}
+	amount := "#,##0.00"
+	amountStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &amount})
+	if err != nil {
+		return fmt.Errorf("error creating amount style: %v", err)
+	}
+
+	for i, header := range EquityHeaders {
+		f.SetCellValue(EquitySheet, getExcelColumn(i)+"1", header)
+	}
+	last := getExcelColumn(len(EquityHeaders) - 1)
+	f.SetCellStyle(EquitySheet, "A1", last+"1", bold)
+
+	row := 2
+	for _, g := range report.Groups {
+		values := []interface{}{g.Dimension, g.Name, g.Women.Count, g.Men.Count}
+		women, men := g.Women.Reported(), g.Men.Reported()
+		values = append(values,
+			statistic(women, g.Women.MedianNewSalary), statistic(men, g.Men.MedianNewSalary), statistic(women && men, g.MedianGap()),
+			statistic(women, g.Women.MeanNewSalary), statistic(men, g.Men.MeanNewSalary), statistic(women && men, g.MeanGap()),
+			statistic(women && men, g.MeanGapBefore()),
+			statistic(women, g.Women.MeanIncrease), statistic(men, g.Men.MeanIncrease),
+			statistic(women, g.Women.MedianIncrease), statistic(men, g.Men.MedianIncrease),
+		)
+		for i, value := range values {
+			f.SetCellValue(EquitySheet, fmt.Sprintf("%s%d", getExcelColumn(i), row), value)
+		}
+		f.SetCellStyle(EquitySheet, fmt.Sprintf("E%d", row), fmt.Sprintf("%s%d", last, row), amountStyle)
+		row++
+	}
+
+	// The adjusted gap and what it covers below the groups
+	row++
+	summary := []struct {
+		Name  string
+		Value interface{}
+	}{
+		{"AdjustedGap", round2(report.AdjustedGap)},
+		{"AdjustedGapBefore", round2(report.AdjustedGapBefore)},
+		{"ComparedEmployees", report.Covered},
+		{"AnalysedEmployees", report.Employees},
+		{"SkippedEmployees", len(report.Skipped)},
+		{"AgesOn", report.On.Time},
+		{"MinGroupSize", equity.MinGroupSize},
+	}
+	isoDate := "yyyy-mm-dd"
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &isoDate})
+	if err != nil {
+		return fmt.Errorf("error creating date style: %v", err)
+	}
+	for _, s := range summary {
+		f.SetCellValue(EquitySheet, fmt.Sprintf("A%d", row), s.Name)
+		f.SetCellStyle(EquitySheet, fmt.Sprintf("A%d", row), fmt.Sprintf("A%d", row), bold)
+		f.SetCellValue(EquitySheet, fmt.Sprintf("B%d", row), s.Value)
+		if s.Name == "AgesOn" {
+			f.SetCellStyle(EquitySheet, fmt.Sprintf("B%d", row), fmt.Sprintf("B%d", row), dateStyle)
+		}
+		row++
+	}
+
+	f.SetColWidth(EquitySheet, "A", "A", 20)
+	f.SetColWidth(EquitySheet, "B", "B", 22)
+	f.SetColWidth(EquitySheet, "C", "D", 8)
+	f.SetColWidth(EquitySheet, "E", last, 20)
+
+	if err := f.SaveAs(filename); err != nil {
+		return fmt.Errorf("error saving %s: %v", filename, err)
+	}
+	return nil
+}
+
+// statistic returns a value rounded to øre if it is reported, or nil for an
+// empty cell
+func statistic(reported bool, value float64) interface{} {
+	if !reported {
+		return nil
+	}
+	return round2(value)
+}
@@ -0,0 +1,77 @@
+package excel
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// derivedColumn is a column calculated from other columns of the same row.
+// {Header} in Formula refers to that column's cell, {payoutMonth} to the
+// campaign's payout month counted as year*12 + month, and {maxMonths} to
+// the campaign's longest back pay period in months.
+type derivedColumn struct {
+	Header  string
+	Formula string
+}
+
+// derivedColumns are written as formulas with Options.Formulas, in the
+// order they depend on each other. They calculate as the generator does, so
+// until inputs are edited they give the generated values, at most an øre
+// apart where Excel rounds a half øre differently. ChangeDescription and
+// LetterContent are text and keep the generated amounts; the letters are
+// rendered from the columns and do not use them.
+//
+// BackPay counts the retroactive months from EffectiveDate as
+// Campaign.RetroactiveMonths does: the rest of the effective month pro rata
+// by days, then whole months up to the payout month, at most from the start
+// of the campaign year. It is written in a form
+// excelize evaluates correctly when reading, which rules out IF.
+var derivedColumns = []derivedColumn{
+	{"IndividualAdjustment", "ROUND({BaseSalary}*{PercentageIncrease}/100,2)"},
+	{"NewBaseSalary", "{BaseSalary}+{GeneralAdjustment}+{IndividualAdjustment}"},
+	{"NewFullTimeBaseSalary", "ROUND({NewBaseSalary}/({HoursPerWeek}/" + strconv.FormatFloat(models.FullTimeHours, 'g', -1, 64) + "),2)"},
+	{"NewGrossSalary", "ROUND({NewBaseSalary}*{GrossSalary}/{BaseSalary},2)"},
+	{"NewPensionContribution", "ROUND({NewBaseSalary}*({PensionRate}+{PensionIncrease}),0)/100"},
+	{"BackPay", "ROUND(MIN({maxMonths},MAX(0,{payoutMonth}-YEAR({EffectiveDate})*12-MONTH({EffectiveDate})-DAY({EffectiveDate})/DAY(EOMONTH({EffectiveDate},0))" +
+		"+1/DAY(EOMONTH({EffectiveDate},0))))*({NewGrossSalary}-{GrossSalary}),2)"},
+}
+
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// writeFormulas replaces the derived columns' values with formulas. The
+// values stay in the cells as cached results, and the workbook is marked to
+// be recalculated when it is opened.
+func writeFormulas(f *excelize.File, employees []models.EmployeeData, campaign models.Campaign) error {
+	payoutMonth := strconv.Itoa(campaign.Year*12 + int(campaign.PayoutMonth))
+	maxMonths := strconv.Itoa(campaign.MaxRetroactiveMonths())
+	for i := range employees {
+		row := i + 2
+		for _, d := range derivedColumns {
+			formula := placeholderPattern.ReplaceAllStringFunc(d.Formula, func(placeholder string) string {
+				name := placeholder[1 : len(placeholder)-1]
+				switch name {
+				case "payoutMonth":
+					return payoutMonth
+				case "maxMonths":
+					return maxMonths
+				}
+				return fmt.Sprintf("%s%d", getExcelColumn(headerIndex(name)), row)
+			})
+			cell := fmt.Sprintf("%s%d", getExcelColumn(headerIndex(d.Header)), row)
+			if err := f.SetCellFormula(SheetName, cell, formula); err != nil {
+				return fmt.Errorf("error writing formula in %s: %v", cell, err)
+			}
+		}
+	}
+
+	fullCalcOnLoad := true
+	if err := f.SetCalcProps(&excelize.CalcPropsOptions{FullCalcOnLoad: &fullCalcOnLoad}); err != nil {
+		return fmt.Errorf("error setting calculation properties: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package excel
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestBackPayFormulaFollowsEffectiveDate(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Rows = 20
+	opts.Seed = 1
+	opts.Formulas = true
+	filename := filepath.Join(t.TempDir(), "formulas.xlsx")
+	if err := Generate(filename, opts); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	// Move the effective dates of the first rows, including into and past
+	// the payout month
+	dates := []models.Date{
+		models.NewDate(2025, time.January, 1),
+		models.NewDate(2025, time.March, 15),
+		models.NewDate(2025, time.February, 28),
+		models.NewDate(2024, time.December, 31),
+		models.NewDate(2025, time.May, 31),
+		models.NewDate(2025, time.June, 1),
+		models.NewDate(2025, time.August, 20),
+	}
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := getExcelColumn(headerIndex("EffectiveDate"))
+	for i, date := range dates {
+		if err := f.SetCellValue(SheetName, fmt.Sprintf("%s%d", col, i+2), date.Time); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	employees, err := ReadEmployees(filename)
+	if err != nil {
+		t.Fatalf("ReadEmployees: %v", err)
+	}
+	for i, date := range dates {
+		emp := employees[i]
+		if !emp.EffectiveDate.Equal(date.Time) {
+			t.Fatalf("row %d: EffectiveDate %s, want %s", i+2, emp.EffectiveDate, date)
+		}
+		months := opts.Campaign.RetroactiveMonths(date)
+		want := round2(months * (emp.Amount("NewGrossSalary") - emp.Amount("GrossSalary")))
+		if got := emp.Amount("BackPay"); got != want {
+			t.Errorf("effective %s (%.4f months): BackPay %.2f, want %.2f", date, months, got, want)
+		}
+	}
+}
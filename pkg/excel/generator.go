@@ -2,9 +2,13 @@ package excel
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
+	"strconv"
 	"time"
 
+	"dsb-excel-generator/pkg/models"
+
 	"github.com/xuri/excelize/v2"
 )
 
@@ -35,9 +39,9 @@ var danishLastNames = []string{
 	"Kofoed", "Danielsen", "Thygesen", "Nygaard", "Winther", "Holst", "Rosendahl",
 }
 
-// Letter types
-var letterTypes = []string{
-	"Salary Regulation 2025",
+// LetterTypes lists the letter types the generator can produce
+var LetterTypes = []string{
+	"Salary Regulation",
 	"Pension Change",
 	"Contract Amendment",
 	"Annual Salary Review",
@@ -49,209 +53,350 @@ var departments = []string{
 	"Marketing", "Sales", "Logistics", "Administration", "Legal",
 }
 
-// Manager names (using the same name lists)
-func getRandomManagerName() string {
-	return danishFirstNames[rand.Intn(len(danishFirstNames))] + " " +
-		danishLastNames[rand.Intn(len(danishLastNames))]
-}
-
 // Document types for P360
 var documentTypes = []string{
 	"Salary Letter", "Contract Amendment", "Pension Notice", "HR Communication",
 }
 
-// Security levels for P360
-var securityLevels = []string{
+// SecurityLevels lists the P360 security levels
+var SecurityLevels = []string{
 	"Internal", "Confidential", "Strictly Confidential",
 }
 
-// Generate creates the Excel file with mock data
-func Generate(filename string) error {
-	rand.Seed(time.Now().UnixNano())
+// Headers lists the workbook columns in the order they are written
+var Headers = []string{
+	"CPR", "FirstName", "LastName", "EmployeeNumber", "Department", "Role", "Agreement",
+	"EmploymentType", "HoursPerWeek", "Seniority", "SalaryStep",
+	"Street", "HouseNumber", "PostCode", "City",
+	"BaseSalary", "Supplements", "NewBaseSalary", "FullTimeBaseSalary", "NewFullTimeBaseSalary",
+	"GrossSalary", "NewGrossSalary",
+	"GeneralAdjustment", "IndividualAdjustment", "PercentageIncrease", "EffectiveDate", "BackPay",
+	"PensionIncrease", "PensionRate", "EmployeePensionRate", "EmployerPensionRate",
+	"PensionContribution", "NewPensionContribution",
+	"LetterType", "ChangeDescription", "ManagerEmployeeNumber", "ManagerName", "AdditionalNotes",
+	"ApprovalStatus", "ApprovedBy", "DeliveryMethod", "DocumentType", "CaseNumber", "SecurityLevel", "LetterContent",
+}
 
-	f := excelize.NewFile()
-	defer f.Close()
+// SheetName is the worksheet holding the employee rows
+const SheetName = "Sheet1"
+
+// maxRows is the number of five-digit employee and case numbers
+const maxRows = 99999
+
+// Options controls the generated workbook
+type Options struct {
+	// Rows is the number of employee rows to generate
+	Rows int
+	// Seed makes the generated data reproducible; 0 uses the current time
+	Seed int64
+	// Campaign supplies the year, agreement and payout texts of the letters
+	Campaign models.Campaign
+	// EffectiveDates are the rules effective dates are drawn from, by weight
+	EffectiveDates []DateRule
+	// LetterTypes are drawn with the given weights; values must be in LetterTypes
+	LetterTypes []Weighted
+	// Agreements are the agreement codes employees are assigned to, drawn by
+	// weight; values must be agreements of the campaign
+	Agreements []Weighted
+	// EmploymentTypes are drawn with the given weights; values must be in
+	// models.EmploymentTypes
+	EmploymentTypes []Weighted
+	// PayScale is the salary scale employees are placed on
+	PayScale PayScale
+	// DepartmentSteps places each department's employees on the scale by seniority
+	DepartmentSteps map[string]StepRange
+	// Supplements are drawn for each employee on top of the scale salary
+	Supplements []Supplement
+	// Budgets are the departments' pools for individual adjustments
+	Budgets []Budget
+	// AllocateBudgets scales the individual adjustments of departments over
+	// budget down so they fit their pools
+	AllocateBudgets bool
+	// Formulas writes IndividualAdjustment, NewBaseSalary and the other
+	// derived columns as formulas over the input columns, so they follow
+	// edits to PercentageIncrease
+	Formulas bool
+	// HighlightIncrease is the individual increase, in percent, above which
+	// PercentageIncrease is highlighted for review; 0 uses
+	// DefaultHighlightIncrease
+	HighlightIncrease float64
+	// Protect locks every column but EditableHeaders, and every other
+	// sheet. It implies Formulas, so edits to PercentageIncrease keep the
+	// derived columns correct.
+	Protect bool
+	// Password protects the sheets and the workbook structure when Protect
+	// is set; empty protects without a password
+	Password string
+}
+
+// Weighted is a value drawn with a relative weight
+type Weighted struct {
+	Value  string
+	Weight int
+}
 
-	sheetName := "Sheet1"
+// DefaultOptions returns the options used when nothing else is configured
+func DefaultOptions() Options {
+	campaign := models.DefaultCampaign()
 
-	// Set column headers - expanded for P360 integration
-	headers := []string{
-		"CPR", "FirstName", "LastName", "EmployeeNumber", "Department",
-		"BaseSalary", "NewBaseSalary", "GrossSalary", "NewGrossSalary",
-		"IndividualAdjustment", "PercentageIncrease", "EffectiveDate", "PensionIncrease",
-		"LetterType", "ChangeDescription", "ManagerName", "AdditionalNotes",
-		"DocumentType", "CaseNumber", "SecurityLevel", "LetterContent",
+	opts := Options{
+		Rows:           3000,
+		Campaign:       campaign,
+		EffectiveDates: DefaultEffectiveDates(campaign.Year),
 	}
-
-	// Write headers
-	for i, header := range headers {
-		col := getExcelColumn(i)
-		f.SetCellValue(sheetName, col+"1", header)
+	for _, letterType := range LetterTypes {
+		opts.LetterTypes = append(opts.LetterTypes, Weighted{letterType, 1})
 	}
+	opts.Agreements = []Weighted{{"HK", 4}, {"3F", 2}, {"DJØF", 1}, {"Dansk Metal", 2}}
+	opts.EmploymentTypes = []Weighted{{models.EmploymentMonthly, 16}, {models.EmploymentHourly, 2}, {models.EmploymentTrainee, 2}}
+	opts.PayScale = DefaultPayScale()
+	opts.DepartmentSteps = DefaultDepartmentSteps()
+	opts.Supplements = DefaultSupplements()
+	opts.HighlightIncrease = DefaultHighlightIncrease
+	return opts
+}
 
-	// Track used CPR numbers to ensure uniqueness
-	usedCPRs := make(map[string]bool)
+// IsLetterType reports whether the generator supports the letter type
+func IsLetterType(letterType string) bool {
+	for _, t := range LetterTypes {
+		if t == letterType {
+			return true
+		}
+	}
+	return false
+}
 
-	// Generate 3000 rows of data
-	for row := 2; row <= 3001; row++ {
-		// Generate unique CPR number (DDMMYY-XXXX)
-		var cpr string
-		for {
-			cpr = generateCPR()
-			if !usedCPRs[cpr] {
-				usedCPRs[cpr] = true
-				break
-			}
+// IsDepartment reports whether the department is one of the generated departments
+func IsDepartment(department string) bool {
+	for _, d := range departments {
+		if d == department {
+			return true
 		}
+	}
+	return false
+}
+
+// IsHeader reports whether the name is one of Headers
+func IsHeader(name string) bool {
+	return headerIndex(name) >= 0
+}
 
-		// Generate name
-		firstName := danishFirstNames[rand.Intn(len(danishFirstNames))]
-		lastName := danishLastNames[rand.Intn(len(danishLastNames))]
-
-		// Generate salaries (25,000 - 75,000 kr)
-		// Add more realistic variation with decimal places
-		baseSalary := float64(rand.Intn(50000)+25000) + rand.Float64()*1000
-
-		// Calculate individual adjustment (0.5% to 5% increase)
-		// Some employees get higher increases
-		percentageIncrease := 0.5 + rand.Float64()*4.5
-		// Round to 2 decimal places for realism
-		percentageIncrease = float64(int(percentageIncrease*100)) / 100
-
-		individualAdjustment := baseSalary * (percentageIncrease / 100)
-		newBaseSalary := baseSalary + individualAdjustment
-
-		// Gross salary includes some additional compensation (about 10-25% more)
-		// Variation depends on seniority/role
-		additionalComp := 1.1 + rand.Float64()*0.15
-		grossSalary := baseSalary * additionalComp
-		newGrossSalary := newBaseSalary * additionalComp
-
-		// Pension increase - mostly 1.00% but some variation for different agreements
-		pensionIncrease := 1.00
-		if rand.Float64() < 0.1 { // 10% get different pension rates
-			pensionIncrease = 0.5 + rand.Float64()*1.5 // 0.5% to 2%
-			pensionIncrease = float64(int(pensionIncrease*100)) / 100
+// isEmploymentType reports whether the employment type is known
+func isEmploymentType(employmentType string) bool {
+	for _, t := range models.EmploymentTypes {
+		if t == employmentType {
+			return true
 		}
+	}
+	return false
+}
 
-		// Effective date - most are 1. marts 2025, but some have different dates
-		effectiveDates := []string{
-			"1. marts 2025", "1. marts 2025", "1. marts 2025", "1. marts 2025",
-			"1. april 2025", "1. maj 2025", "1. februar 2025",
+// weeklyHours draws the weekly working hours for an employment type: most
+// monthly employees work full time, hourly employees 8 to 30 hours
+func weeklyHours(employmentType string) float64 {
+	switch employmentType {
+	case models.EmploymentHourly:
+		return float64(8 + rng.Intn(23))
+	case models.EmploymentMonthly:
+		if rng.Float64() < 0.25 {
+			partTime := []float64{32, 30, 28, 25, 20}
+			return partTime[rng.Intn(len(partTime))]
 		}
-		effectiveDate := effectiveDates[rand.Intn(len(effectiveDates))]
-
-		// Generate additional fields
-		employeeNumber := fmt.Sprintf("EMP%05d", row-1)
-		department := departments[rand.Intn(len(departments))]
-		letterType := letterTypes[rand.Intn(len(letterTypes))]
-		managerName := getRandomManagerName()
-		documentType := documentTypes[rand.Intn(len(documentTypes))]
-		caseNumber := fmt.Sprintf("2025-%05d", rand.Intn(99999)+1)
-		securityLevel := securityLevels[rand.Intn(len(securityLevels))]
-
-		// Generate change description based on letter type
-		var changeDescription string
-		switch letterType {
-		case "Salary Regulation 2025":
-			changeDescription = fmt.Sprintf("Individual salary increase of %.2f%% effective %s", percentageIncrease, effectiveDate)
-		case "Pension Change":
-			changeDescription = fmt.Sprintf("Pension contribution increase to %.2f%%", pensionIncrease)
-		case "Contract Amendment":
-			changeDescription = fmt.Sprintf("Contract update with new salary terms from %s", effectiveDate)
-		case "Annual Salary Review":
-			changeDescription = fmt.Sprintf("Annual review resulting in %.2f%% increase", percentageIncrease)
+	}
+	return models.FullTimeHours
+}
+
+// pickWeighted draws a value from a weighted list
+func pickWeighted(values []Weighted) string {
+	weights := make([]int, len(values))
+	for i, v := range values {
+		weights[i] = v.Weight
+	}
+	return values[weightedIndex(weights)].Value
+}
+
+// weightedIndex draws an index with probability proportional to its weight
+func weightedIndex(weights []int) int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	n := rng.Intn(total)
+	for i, w := range weights {
+		if n < w {
+			return i
 		}
+		n -= w
+	}
+	return len(weights) - 1
+}
 
-		// Additional notes - 30% of employees get notes
-		additionalNotes := ""
-		if rand.Float64() < 0.3 {
-			notes := []string{
-				"Please confirm receipt by signing and returning this letter",
-				"Questions? Contact HR at hr@company.dk",
-				"This change was approved by your department manager",
-				"No action required from your side",
-				"Tax implications will be detailed in your next payslip",
-			}
-			additionalNotes = notes[rand.Intn(len(notes))]
+// rng is the random source for all generated data
+var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// Generate creates the Excel file with mock data
+func Generate(filename string, opts Options) error {
+	if opts.Rows <= 0 {
+		return fmt.Errorf("number of rows must be positive, got %d", opts.Rows)
+	}
+	if opts.Rows > maxRows {
+		return fmt.Errorf("at most %d rows can have unique employee and case numbers, got %d", maxRows, opts.Rows)
+	}
+	defaults := DefaultOptions()
+	if opts.Campaign.Title == "" {
+		opts.Campaign = defaults.Campaign
+	}
+	if len(opts.EffectiveDates) == 0 {
+		opts.EffectiveDates = defaults.EffectiveDates
+	}
+	if len(opts.LetterTypes) == 0 {
+		opts.LetterTypes = defaults.LetterTypes
+	}
+	if len(opts.EmploymentTypes) == 0 {
+		opts.EmploymentTypes = defaults.EmploymentTypes
+	}
+	for _, v := range opts.EmploymentTypes {
+		if v.Weight <= 0 || !isEmploymentType(v.Value) {
+			return fmt.Errorf("employment type %q needs a positive weight and must be one of %v", v.Value, models.EmploymentTypes)
+		}
+	}
+	if len(opts.PayScale.Steps) == 0 {
+		opts.PayScale = defaults.PayScale
+	}
+	if opts.DepartmentSteps == nil {
+		opts.DepartmentSteps = defaults.DepartmentSteps
+	}
+	if opts.Supplements == nil {
+		opts.Supplements = defaults.Supplements
+	}
+	if opts.HighlightIncrease == 0 {
+		opts.HighlightIncrease = defaults.HighlightIncrease
+	}
+	if opts.Protect {
+		opts.Formulas = true
+	}
+	trainees := false
+	for _, v := range opts.EmploymentTypes {
+		trainees = trainees || v.Value == models.EmploymentTrainee
+	}
+	if err := checkPayScale(opts.PayScale, opts.DepartmentSteps, opts.Supplements, trainees); err != nil {
+		return err
+	}
+	if len(opts.Campaign.Agreements) == 0 {
+		return fmt.Errorf("campaign %q has no agreements", opts.Campaign.Title)
+	}
+	if len(opts.Agreements) == 0 {
+		for _, a := range opts.Campaign.Agreements {
+			opts.Agreements = append(opts.Agreements, Weighted{a.Code, 1})
 		}
+	}
+	for _, v := range append(append([]Weighted{}, opts.LetterTypes...), opts.Agreements...) {
+		if v.Weight <= 0 {
+			return fmt.Errorf("weight of %q must be positive, got %d", v.Value, v.Weight)
+		}
+	}
+	dates, err := newDatePicker(opts.EffectiveDates)
+	if err != nil {
+		return err
+	}
+	for _, v := range opts.LetterTypes {
+		if !IsLetterType(v.Value) {
+			return fmt.Errorf("unknown letter type %q", v.Value)
+		}
+	}
+	for _, v := range opts.Agreements {
+		if opts.Campaign.AgreementFor(v.Value).Code != v.Value {
+			return fmt.Errorf("unknown agreement %q", v.Value)
+		}
+	}
+
+	if err := checkBudgets(opts.Budgets); err != nil {
+		return err
+	}
 
-		// Generate full letter content
-		letterContent := generateLetterContent(
-			firstName, lastName,
-			fmt.Sprintf("%.2f", baseSalary),
-			fmt.Sprintf("%.2f", newBaseSalary),
-			fmt.Sprintf("%.2f", grossSalary),
-			fmt.Sprintf("%.2f", newGrossSalary),
-			fmt.Sprintf("%.2f", individualAdjustment),
-			fmt.Sprintf("%.2f", percentageIncrease),
-			effectiveDate,
-			fmt.Sprintf("%.2f", pensionIncrease),
-			letterType,
-		)
-
-		// Write data to cells using column helper
-		col := 0
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), cpr)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), firstName)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), lastName)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), employeeNumber)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), department)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), fmt.Sprintf("%.2f", baseSalary))
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), fmt.Sprintf("%.2f", newBaseSalary))
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), fmt.Sprintf("%.2f", grossSalary))
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), fmt.Sprintf("%.2f", newGrossSalary))
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), fmt.Sprintf("%.2f", individualAdjustment))
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), fmt.Sprintf("%.2f", percentageIncrease))
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), effectiveDate)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), fmt.Sprintf("%.2f", pensionIncrease))
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), letterType)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), changeDescription)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), managerName)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), additionalNotes)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), documentType)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), caseNumber)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), securityLevel)
-		col++
-		f.SetCellValue(sheetName, getExcelColumn(col)+fmt.Sprintf("%d", row), letterContent)
-
-		if row%100 == 0 {
-			fmt.Printf("Generated %d rows...\n", row-1)
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	employees := generateEmployees(seed, dates, opts, nil)
+	if opts.AllocateBudgets && len(opts.Budgets) > 0 {
+		// Generate the same rows again with the individual adjustments of
+		// departments over budget scaled down into their pools
+		if scales := budgetScales(employees, opts.Budgets); len(scales) > 0 {
+			fmt.Printf("Allocating individual adjustments within the budgets of %d departments...\n", len(scales))
+			employees = generateEmployees(seed, dates, opts, scales)
 		}
 	}
 
-	// Set column widths for better readability
-	for i := 0; i < len(headers); i++ {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// Write headers
+	for i, header := range Headers {
 		col := getExcelColumn(i)
-		width := 18.0
+		f.SetCellValue(SheetName, col+"1", header)
+	}
+
+	// Dates are stored as date cells shown as ISO dates
+	isoDate := "yyyy-mm-dd"
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &isoDate})
+	if err != nil {
+		return fmt.Errorf("error creating date style: %v", err)
+	}
 
-		// Wider columns for text-heavy fields
-		if headers[i] == "LetterContent" {
-			width = 80.0
-		} else if headers[i] == "ChangeDescription" || headers[i] == "AdditionalNotes" {
-			width = 40.0
+	// Numeric columns are number cells, so formulas can calculate with them.
+	// Amounts, rates and percentages show two decimals.
+	decimalStyle, err := f.NewStyle(&excelize.Style{NumFmt: 2})
+	if err != nil {
+		return fmt.Errorf("error creating number style: %v", err)
+	}
+
+	for i, emp := range employees {
+		row := i + 2
+
+		// Write data to cells in header order
+		for i, header := range Headers {
+			cell := getExcelColumn(i) + fmt.Sprintf("%d", row)
+			value, _ := emp.Value(header)
+			if date, ok := value.(models.Date); ok {
+				f.SetCellValue(SheetName, cell, date.Time)
+				f.SetCellStyle(SheetName, cell, cell, dateStyle)
+				continue
+			}
+			if text, ok := value.(string); ok && isNumericHeader(header) {
+				if number, err := strconv.ParseFloat(text, 64); err == nil {
+					f.SetCellValue(SheetName, cell, number)
+					if !wholeNumberHeaders[header] {
+						f.SetCellStyle(SheetName, cell, cell, decimalStyle)
+					}
+					continue
+				}
+			}
+			f.SetCellValue(SheetName, cell, value)
 		}
+	}
 
-		f.SetColWidth(sheetName, col, col, width)
+	if err := formatEmployeeSheet(f, employees); err != nil {
+		return err
+	}
+	if err := formatForReview(f, opts.HighlightIncrease); err != nil {
+		return err
+	}
+	if opts.Formulas {
+		if err := writeFormulas(f, employees, opts.Campaign); err != nil {
+			return err
+		}
+	}
+	if err := writeOrgChart(f, employees); err != nil {
+		return err
+	}
+	if err := writeSummarySheets(f); err != nil {
+		return err
+	}
+	if opts.Protect {
+		if err := protectWorkbook(f, len(employees), opts.Password); err != nil {
+			return err
+		}
 	}
 
 	// Save the file
@@ -259,10 +404,292 @@ func Generate(filename string) error {
 		return fmt.Errorf("error saving file: %v", err)
 	}
 
-	fmt.Printf("\nSuccessfully generated %s with 3000 rows of data!\n", filename)
+	fmt.Printf("\nSuccessfully generated %s with %d rows of data!\n", filename, opts.Rows)
 	return nil
 }
 
+// generateEmployees generates the rows from the seed, so the same seed gives
+// the same rows. Individual adjustment percentages of the departments in
+// scales are multiplied by their factor.
+func generateEmployees(seed int64, dates *datePicker, opts Options, scales map[string]float64) []models.EmployeeData {
+	rng = rand.New(rand.NewSource(seed))
+
+	// Track used CPR and case numbers to ensure uniqueness
+	usedCPRs := make(map[string]bool)
+	usedCases := make(map[string]bool)
+
+	// Place every employee in a department's organisation, then generate
+	// the rows. Managers are filled in once everyone has a name.
+	plan := planOrganisation(opts.Rows)
+	employees := make([]models.EmployeeData, opts.Rows)
+	for i := range employees {
+		scale := 1.0
+		if s, ok := scales[plan[i].Department]; ok {
+			scale = s
+		}
+		employees[i] = generateEmployee(i+1, plan[i], scale, usedCPRs, usedCases, dates, opts)
+		if (i+1)%100 == 0 {
+			fmt.Printf("Generated %d rows...\n", i+1)
+		}
+	}
+	for i, pos := range plan {
+		if pos.Manager > 0 {
+			manager := employees[pos.Manager-1]
+			employees[i].ManagerEmployeeNumber = manager.EmployeeNumber
+			employees[i].ManagerName = manager.FullName()
+		}
+	}
+	return employees
+}
+
+// generateEmployee creates the mock data for a single employee row. The
+// individual adjustment percentage is multiplied by adjustmentScale.
+func generateEmployee(index int, pos position, adjustmentScale float64, usedCPRs, usedCases map[string]bool, dates *datePicker, opts Options) models.EmployeeData {
+	// Generate unique CPR number (DDMMYY-XXXX)
+	var cpr string
+	var birthDate models.Date
+	for {
+		cpr, birthDate = generateCPR()
+		if !usedCPRs[cpr] {
+			usedCPRs[cpr] = true
+			break
+		}
+	}
+
+	// Generate name
+	firstName := danishFirstNames[rng.Intn(len(danishFirstNames))]
+	lastName := danishLastNames[rng.Intn(len(danishLastNames))]
+
+	// Collective agreement, which sets the general increase, the pension
+	// rates and the agreed pension increase
+	agreement := opts.Campaign.AgreementFor(pickWeighted(opts.Agreements))
+
+	// Employment type and weekly working hours. Department heads and team
+	// leads are monthly paid and work full time.
+	employmentType := pickWeighted(opts.EmploymentTypes)
+	hours := weeklyHours(employmentType)
+	if pos.Role != models.RoleStaff {
+		employmentType, hours = models.EmploymentMonthly, models.FullTimeHours
+	}
+
+	// Seniority in whole years, at most the years since turning 18.
+	// Trainees are in their first to fourth year.
+	age := models.AgeOn(birthDate, models.NewDate(opts.Campaign.Year, time.January, 1))
+	seniority := 0
+	if employmentType == models.EmploymentTrainee {
+		seniority = rng.Intn(traineeYears)
+	} else if age > 18 {
+		seniority = rng.Intn(min(age-18, 40) + 1)
+		seniority = max(seniority, min(age-18, managerSeniority[pos.Role]))
+	}
+
+	// The full-time base salary is the scale salary of the department's step
+	// for the seniority plus supplements. Trainees are on the first steps
+	// without supplements.
+	department := pos.Department
+	step := opts.DepartmentSteps[department].Step(seniority)
+	supplements := pickSupplements(opts.Supplements, seniority, pos.Role)
+	if employmentType == models.EmploymentTrainee {
+		step, supplements = 1+seniority, 0
+	}
+	fullTimeBaseSalary := opts.PayScale.Salary(step) + supplements
+
+	// Actual amounts are pro-rated by the weekly hours
+	factor := models.FullTimeFactor(hours)
+	baseSalary := round2(fullTimeBaseSalary * factor)
+	supplements = round2(supplements * factor)
+
+	// The scale and supplements are regulated by the agreement's general increase
+	generalAdjustment := round2(baseSalary * agreement.GeneralIncrease / 100)
+
+	// Calculate individual adjustment (0.5% to 5% increase, trainees up to 1.5%)
+	// Some employees get higher increases
+	percentageIncrease := 0.5 + rng.Float64()*4.5
+	if employmentType == models.EmploymentTrainee {
+		percentageIncrease = 0.5 + rng.Float64()
+	}
+	// Round to 2 decimal places for realism
+	percentageIncrease = float64(int(percentageIncrease*100)) / 100
+	// Scaled down to fit the department's budget, if it has one. The scaled
+	// percentage is rounded down, so the adjustment never grows by more
+	// than the øre budgetScales keeps for rounding it.
+	if adjustmentScale < 1 {
+		percentageIncrease = math.Floor(percentageIncrease*adjustmentScale*100) / 100
+	}
+
+	individualAdjustment := round2(baseSalary * (percentageIncrease / 100))
+	newBaseSalary := baseSalary + generalAdjustment + individualAdjustment
+	newFullTimeBaseSalary := round2(newBaseSalary / factor)
+
+	// Gross salary includes some additional compensation (about 10-25% more)
+	// Variation depends on seniority/role
+	additionalComp := 1.1 + rng.Float64()*0.15
+	// Rounded to øre as stored, so the back pay reconciles with the columns.
+	// The new gross salary keeps the stored ratio, as the formula does.
+	grossSalary := round2(baseSalary * additionalComp)
+	newGrossSalary := round2(newBaseSalary * grossSalary / baseSalary)
+
+	pensionIncrease := agreement.PensionIncrease
+	pension := models.Pension{
+		EmployeeRate:  agreement.EmployeePensionRate,
+		EmployerRate:  agreement.EmployerPensionRate,
+		Increase:      pensionIncrease,
+		BaseSalary:    baseSalary,
+		NewBaseSalary: newBaseSalary,
+	}
+
+	// Effective date drawn from the configured dates
+	effectiveDate := dates.pick()
+
+	// Back pay for the months between the effective date and the payout
+	backPay := opts.Campaign.BackPay(effectiveDate, grossSalary, newGrossSalary)
+
+	// Generate additional fields
+	employeeNumber := fmt.Sprintf("EMP%05d", index)
+	letterType := pickWeighted(opts.LetterTypes)
+	documentType := documentTypes[rng.Intn(len(documentTypes))]
+	var caseNumber string
+	for {
+		caseNumber = fmt.Sprintf("%d-%05d", opts.Campaign.Year, rng.Intn(99999)+1)
+		if !usedCases[caseNumber] {
+			usedCases[caseNumber] = true
+			break
+		}
+	}
+	securityLevel := SecurityLevels[rng.Intn(len(SecurityLevels))]
+	street, houseNumber, postCode, city := generateAddress()
+
+	// About a fifth of employees still receive printed letters
+	deliveryMethod := models.DeliveryDigital
+	if rng.Float64() < 0.2 {
+		deliveryMethod = models.DeliveryPhysical
+	}
+
+	// Generate change description based on letter type
+	var changeDescription string
+	switch letterType {
+	case "Salary Regulation":
+		changeDescription = fmt.Sprintf("Individual salary increase of %.2f%% effective %s", percentageIncrease, effectiveDate.Danish())
+	case "Pension Change":
+		changeDescription = fmt.Sprintf("Pension contribution increase to %.2f%%", pensionIncrease)
+	case "Contract Amendment":
+		changeDescription = fmt.Sprintf("Contract update with new salary terms from %s", effectiveDate.Danish())
+	case "Annual Salary Review":
+		changeDescription = fmt.Sprintf("Annual review resulting in %.2f%% increase", percentageIncrease)
+	}
+
+	// Additional notes - 30% of employees get notes
+	additionalNotes := ""
+	if rng.Float64() < 0.3 {
+		notes := []string{
+			"Please confirm receipt by signing and returning this letter",
+			"Questions? Contact HR at hr@company.dk",
+			"This change was approved by your department manager",
+			"No action required from your side",
+			"Tax implications will be detailed in your next payslip",
+		}
+		additionalNotes = notes[rng.Intn(len(notes))]
+	}
+
+	// Generate full letter content
+	letterContent := generateLetterContent(
+		opts.Campaign,
+		firstName, lastName,
+		fmt.Sprintf("%.2f", baseSalary),
+		fmt.Sprintf("%.2f", newBaseSalary),
+		fmt.Sprintf("%.2f", grossSalary),
+		fmt.Sprintf("%.2f", newGrossSalary),
+		fmt.Sprintf("%.2f", individualAdjustment),
+		fmt.Sprintf("%.2f", percentageIncrease),
+		fmt.Sprintf("%.2f", (newBaseSalary-baseSalary)/baseSalary*100),
+		models.PlacementText(step, seniority, supplements),
+		models.HoursText(employmentType, hours, newFullTimeBaseSalary),
+		effectiveDate.Danish(),
+		opts.Campaign.BackPayText(effectiveDate, backPay),
+		agreement,
+		pensionOverview(pension),
+		letterType,
+	)
+
+	return models.EmployeeData{
+		CPR:                    cpr,
+		FirstName:              firstName,
+		LastName:               lastName,
+		EmployeeNumber:         employeeNumber,
+		Department:             department,
+		Role:                   pos.Role,
+		Agreement:              agreement.Code,
+		EmploymentType:         employmentType,
+		HoursPerWeek:           fmt.Sprintf("%g", hours),
+		Seniority:              fmt.Sprintf("%d", seniority),
+		SalaryStep:             fmt.Sprintf("%d", step),
+		Street:                 street,
+		HouseNumber:            houseNumber,
+		PostCode:               postCode,
+		City:                   city,
+		BaseSalary:             fmt.Sprintf("%.2f", baseSalary),
+		Supplements:            fmt.Sprintf("%.2f", supplements),
+		NewBaseSalary:          fmt.Sprintf("%.2f", newBaseSalary),
+		FullTimeBaseSalary:     fmt.Sprintf("%.2f", fullTimeBaseSalary),
+		NewFullTimeBaseSalary:  fmt.Sprintf("%.2f", newFullTimeBaseSalary),
+		GrossSalary:            fmt.Sprintf("%.2f", grossSalary),
+		NewGrossSalary:         fmt.Sprintf("%.2f", newGrossSalary),
+		GeneralAdjustment:      fmt.Sprintf("%.2f", generalAdjustment),
+		IndividualAdjustment:   fmt.Sprintf("%.2f", individualAdjustment),
+		PercentageIncrease:     fmt.Sprintf("%.2f", percentageIncrease),
+		EffectiveDate:          effectiveDate,
+		BackPay:                fmt.Sprintf("%.2f", backPay),
+		PensionIncrease:        fmt.Sprintf("%.2f", pensionIncrease),
+		PensionRate:            fmt.Sprintf("%.2f", pension.Rate()),
+		EmployeePensionRate:    fmt.Sprintf("%.2f", pension.EmployeeRate),
+		EmployerPensionRate:    fmt.Sprintf("%.2f", pension.EmployerRate),
+		PensionContribution:    fmt.Sprintf("%.2f", pension.Contribution()),
+		NewPensionContribution: fmt.Sprintf("%.2f", pension.NewContribution()),
+		LetterType:             letterType,
+		ChangeDescription:      changeDescription,
+		AdditionalNotes:        additionalNotes,
+		ApprovalStatus:         models.ApprovalPending,
+		DeliveryMethod:         deliveryMethod,
+		DocumentType:           documentType,
+		CaseNumber:             caseNumber,
+		SecurityLevel:          securityLevel,
+		LetterContent:          letterContent,
+	}
+}
+
+// pensionOverview lists the monthly pension contributions before and after
+// as plain text, one line per share
+func pensionOverview(p models.Pension) string {
+	lines := "Dit månedlige pensionsbidrag før og efter reguleringen:"
+	for _, share := range p.Shares() {
+		lines += fmt.Sprintf("\n• %s: %.2f%% = %.2f kr. før, %.2f%% = %.2f kr. efter",
+			share.Name, share.Rate, share.Contribution, share.NewRate, share.NewContribution)
+	}
+	return lines
+}
+
+// bullets returns lines as bullet points following a line of text
+func bullets(lines []string) string {
+	text := ""
+	for _, line := range lines {
+		text += "\n• " + line
+	}
+	return text
+}
+
+// round2 rounds an amount to øre
+func round2(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// paragraph returns text as a paragraph following another, or nothing if text is empty
+func paragraph(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "\n\n" + text
+}
+
 // getExcelColumn converts column index to Excel column letter(s)
 func getExcelColumn(index int) string {
 	column := ""
@@ -273,46 +700,56 @@ func getExcelColumn(index int) string {
 	return column
 }
 
-// generateCPR generates a fake Danish CPR number in format DDMMYY-XXXX
-func generateCPR() string {
+// generateCPR generates a fake Danish CPR number in format DDMMYY-XXXX and
+// returns it with the birth date. The first sequence digit encodes the
+// century as in real CPR numbers.
+func generateCPR() (string, models.Date) {
 	// Generate a random date between 1960 and 2005
-	year := rand.Intn(46) + 60 // 60-105 (representing 1960-2005)
-	month := rand.Intn(12) + 1
-	day := rand.Intn(28) + 1 // Keep it simple, avoid month-specific day validation
-
-	// Generate random 4-digit sequence
-	sequence := rand.Intn(9000) + 1000
+	year := 1960 + rng.Intn(46)
+	month := rng.Intn(12) + 1
+	day := rng.Intn(28) + 1 // Keep it simple, avoid month-specific day validation
+
+	// Sequences 1000-3999 are born in the 1900s, 4000-4999 from 2000 to 2036
+	sequence := rng.Intn(3000) + 1000
+	if year >= 2000 {
+		sequence = rng.Intn(1000) + 4000
+	}
 
-	return fmt.Sprintf("%02d%02d%02d-%04d", day, month, year, sequence)
+	cpr := fmt.Sprintf("%02d%02d%02d-%04d", day, month, year%100, sequence)
+	return cpr, models.NewDate(year, time.Month(month), day)
 }
 
 // generateLetterContent creates the full personalized letter text
-func generateLetterContent(firstName, lastName, baseSalary, newBaseSalary, grossSalary, newGrossSalary,
-	individualAdjustment, percentageIncrease, effectiveDate, pensionIncrease, letterType string) string {
+func generateLetterContent(campaign models.Campaign, firstName, lastName, baseSalary, newBaseSalary, grossSalary, newGrossSalary,
+	individualAdjustment, percentageIncrease, totalIncrease, placementText, hoursText, effectiveDate, backPayText string,
+	agreement models.Agreement, pensionTable, letterType string) string {
 
 	fullName := firstName + " " + lastName
 
 	switch letterType {
-	case "Salary Regulation 2025":
-		return fmt.Sprintf(`Lønregulering 2025
+	case "Salary Regulation":
+		return fmt.Sprintf(`%s
 
 Kære %s
 
-Lønreguleringen 2025 for HK medarbejdere er nu afsluttet, og i dette brev kan du læse om hvad det betyder for dig.
+%s for %s er nu afsluttet, og i dette brev kan du læse om hvad det betyder for dig.
 
-Følgende regulering er fastlagt i overenskomsten med virkning 1. maj 2025:
-• Forhøjelse af pensionsbidrag med %s%%
+Følgende regulering er fastlagt i overenskomsten »%s« med virkning %s:%s
+
+%s
 
 Din nærmeste leder har besluttet, at du ud over den nævnte stigning i overenskomsten også skal have en individuel lønregulering gældende pr. %s.
 
-Din basisløn er blevet reguleret til %s kr. og din nye bruttoløn udgør nu %s kr. Den individuelle lønregulering på din bruttoløn er %s kr., svarende til en stigning på %s%%.
+Din basisløn er blevet reguleret til %s kr. og din nye bruttoløn udgør nu %s kr. Den individuelle lønregulering på din bruttoløn er %s kr., svarende til en stigning på %s%%.%s%s
 
-Din nye løn er med tilbagevirkende kraft fra den %s.
+Din nye løn er med tilbagevirkende kraft fra den %s.%s
 
-Denne individuelle regulering vil finde sted ved lønudbetalingen ultimo juni måned 2025.
+Denne individuelle regulering vil finde sted ved lønudbetalingen %s.
 
 Med venlig hilsen
-HR Services & Compensation`, fullName, pensionIncrease, effectiveDate, newBaseSalary, newGrossSalary, individualAdjustment, percentageIncrease, effectiveDate)
+HR Services & Compensation`, campaign.Title, fullName, campaign.RegulationName(), agreement.EmployeeGroup(),
+			agreement.Name, agreement.EffectiveDate.Danish(), bullets(agreement.Regulations()), pensionTable, effectiveDate, newBaseSalary, newGrossSalary, individualAdjustment, percentageIncrease, paragraph(placementText), paragraph(hoursText), effectiveDate,
+			paragraph(backPayText), campaign.PayoutText())
 
 	case "Pension Change":
 		return fmt.Sprintf(`Ændring af pensionsbidrag
@@ -321,14 +758,16 @@ Kære %s
 
 Vi ønsker at informere dig om en ændring i dit pensionsbidrag.
 
-Med virkning fra %s vil dit pensionsbidrag blive forhøjet med %s%%.
+Med virkning fra %s vil dit pensionsbidrag blive forhøjet med %.2f%%.
+
+%s
 
 Din nuværende bruttoløn på %s kr. forbliver uændret. Ændringen påvirker kun pensionsbidraget.
 
-Ændringen er en del af den nye overenskomst og vil fremgå af din næste lønseddel.
+Ændringen er en del af den nye overenskomst »%s« og vil fremgå af din næste lønseddel.
 
 Med venlig hilsen
-HR Services & Compensation`, fullName, effectiveDate, pensionIncrease, grossSalary)
+HR Services & Compensation`, fullName, effectiveDate, agreement.PensionIncrease, pensionTable, grossSalary, agreement.Name)
 
 	case "Contract Amendment":
 		return fmt.Sprintf(`Tillæg til ansættelseskontrakt
@@ -353,14 +792,14 @@ Kære %s
 
 Som en del af vores årlige lønregulering har vi glæden af at meddele dig følgende ændringer med virkning fra %s.
 
-Din basisløn forhøjes fra %s kr. til %s kr., hvilket svarer til en stigning på %s%%.
+Din basisløn forhøjes fra %s kr. til %s kr., hvilket svarer til en samlet stigning på %s%%.
 
-Din nye bruttoløn vil udgøre %s kr.
+Din nye bruttoløn vil udgøre %s kr.%s
 
 Denne stigning er baseret på din præstation og udvikling i det forløbne år.
 
 Med venlig hilsen
-HR Services & Compensation`, fullName, effectiveDate, baseSalary, newBaseSalary, percentageIncrease, newGrossSalary)
+HR Services & Compensation`, fullName, effectiveDate, baseSalary, newBaseSalary, totalIncrease, newGrossSalary, paragraph(hoursText))
 
 	default:
 		return "Letter content not available"
@@ -0,0 +1,88 @@
+package excel
+
+import (
+	"fmt"
+
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ManagerReportHeaders lists the columns of a manager report
+var ManagerReportHeaders = []string{
+	"EmployeeNumber", "Name", "ManagerName", "Role", "LetterType",
+	"BaseSalary", "NewBaseSalary", "IndividualAdjustment", "PercentageIncrease", "TotalIncrease",
+}
+
+// WriteManagerReport writes the regulation of a manager's team to a workbook
+// with one sheet, named after the manager, and a totals row. Totals are
+// formulas, so they follow edits to the rows.
+func WriteManagerReport(filename string, manager models.EmployeeData, team []models.EmployeeData) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// Sheet names are limited to 31 characters
+	sheet := []rune(manager.FullName())
+	if len(sheet) > 31 {
+		sheet = sheet[:31]
+	}
+	if err := f.SetSheetName(SheetName, string(sheet)); err != nil {
+		return fmt.Errorf("error naming sheet: %v", err)
+	}
+	name := string(sheet)
+
+	amount := "#,##0.00"
+	amountStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &amount})
+	if err != nil {
+		return fmt.Errorf("error creating amount style: %v", err)
+	}
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("error creating header style: %v", err)
+	}
+	totalStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, CustomNumFmt: &amount})
+	if err != nil {
+		return fmt.Errorf("error creating totals style: %v", err)
+	}
+
+	for i, header := range ManagerReportHeaders {
+		f.SetCellValue(name, getExcelColumn(i)+"1", header)
+	}
+	f.SetCellStyle(name, "A1", getExcelColumn(len(ManagerReportHeaders)-1)+"1", bold)
+
+	for i, emp := range team {
+		row := i + 2
+		values := []interface{}{
+			emp.EmployeeNumber, emp.FullName(), emp.ManagerName, emp.Role, emp.LetterType,
+			emp.Amount("BaseSalary"), emp.Amount("NewBaseSalary"), emp.Amount("IndividualAdjustment"),
+			emp.Amount("PercentageIncrease"), round2(emp.TotalIncrease()),
+		}
+		for j, value := range values {
+			f.SetCellValue(name, fmt.Sprintf("%s%d", getExcelColumn(j), row), value)
+		}
+		f.SetCellStyle(name, fmt.Sprintf("F%d", row), fmt.Sprintf("J%d", row), amountStyle)
+	}
+
+	// Totals: sums of the amounts and the percentages of the summed base salaries
+	last := len(team) + 1
+	total := last + 1
+	cell := func(column string) string { return fmt.Sprintf("%s%d", column, total) }
+	f.SetCellValue(name, cell("A"), "Total")
+	f.SetCellValue(name, cell("B"), fmt.Sprintf("%d employees", len(team)))
+	for _, column := range []string{"F", "G", "H"} {
+		f.SetCellFormula(name, cell(column), fmt.Sprintf("SUM(%s2:%s%d)", column, column, last))
+	}
+	f.SetCellFormula(name, cell("I"), fmt.Sprintf("IF(%s=0,0,ROUND(%s/%s*100,2))", cell("F"), cell("H"), cell("F")))
+	f.SetCellFormula(name, cell("J"), fmt.Sprintf("IF(%s=0,0,ROUND((%s-%s)/%s*100,2))", cell("F"), cell("G"), cell("F"), cell("F")))
+	f.SetCellStyle(name, cell("A"), cell("J"), totalStyle)
+
+	f.SetColWidth(name, "A", "A", 16)
+	f.SetColWidth(name, "B", "C", 26)
+	f.SetColWidth(name, "D", "E", 22)
+	f.SetColWidth(name, "F", "J", 18)
+
+	if err := f.SaveAs(filename); err != nil {
+		return fmt.Errorf("error saving %s: %v", filename, err)
+	}
+	return nil
+}
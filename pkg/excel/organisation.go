@@ -0,0 +1,202 @@
+package excel
+
+import (
+	"fmt"
+	"sort"
+
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// OrgChartSheet is the worksheet showing the organisation of each department
+const OrgChartSheet = "Organisation"
+
+// teamSize is the largest number of staff reporting to a team lead
+const teamSize = 8
+
+// Minimum seniority in years of department heads and team leads, as far as
+// their age allows
+var managerSeniority = map[string]int{
+	models.RoleDepartmentHead: 10,
+	models.RoleTeamLead:       5,
+}
+
+// position is an employee's place in the organisation
+type position struct {
+	Department string
+	Role       string
+	// Manager is the index of the manager's row, or 0 for department heads
+	Manager int
+}
+
+// planOrganisation draws the department of each of n employees and arranges
+// every department as a tree: a department head, team leads reporting to the
+// head, and staff reporting to the team leads. In departments too small for
+// team leads the staff report to the head. Employees are numbered from 1, so
+// the position of employee i is at index i-1.
+func planOrganisation(n int) []position {
+	plan := make([]position, n)
+	members := make(map[string][]int)
+	for i := 1; i <= n; i++ {
+		department := departments[rng.Intn(len(departments))]
+		plan[i-1].Department = department
+		members[department] = append(members[department], i)
+	}
+
+	for _, department := range departments {
+		m := members[department]
+		if len(m) == 0 {
+			continue
+		}
+		rng.Shuffle(len(m), func(i, j int) { m[i], m[j] = m[j], m[i] })
+
+		head := m[0]
+		plan[head-1].Role = models.RoleDepartmentHead
+		staff := m[1:]
+
+		// Just enough team leads that no team is larger than teamSize
+		var leads []int
+		if len(staff) > teamSize {
+			count := (len(staff) + teamSize) / (teamSize + 1)
+			leads, staff = staff[:count], staff[count:]
+		}
+		for _, lead := range leads {
+			plan[lead-1].Role = models.RoleTeamLead
+			plan[lead-1].Manager = head
+		}
+		for i, s := range staff {
+			plan[s-1].Role = models.RoleStaff
+			plan[s-1].Manager = head
+			if len(leads) > 0 {
+				plan[s-1].Manager = leads[i%len(leads)]
+			}
+		}
+	}
+	return plan
+}
+
+// writeOrgChart adds a sheet listing each department from the head down,
+// every manager followed by their reports with names indented by level
+func writeOrgChart(f *excelize.File, employees []models.EmployeeData) error {
+	if _, err := f.NewSheet(OrgChartSheet); err != nil {
+		return fmt.Errorf("error creating sheet %s: %v", OrgChartSheet, err)
+	}
+	headers := []string{"Department", "Role", "EmployeeNumber", "Name", "ManagerEmployeeNumber", "DirectReports"}
+	for i, header := range headers {
+		f.SetCellValue(OrgChartSheet, getExcelColumn(i)+"1", header)
+	}
+
+	// One indentation style per level of the tree
+	indents := make([]int, len(models.Roles))
+	for level := range indents {
+		style, err := f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Indent: level * 2}})
+		if err != nil {
+			return fmt.Errorf("error creating org chart style: %v", err)
+		}
+		indents[level] = style
+	}
+
+	tree := models.NewOrgTree(employees)
+	heads := append([]models.EmployeeData{}, tree.Heads()...)
+	sort.SliceStable(heads, func(i, j int) bool { return heads[i].Department < heads[j].Department })
+
+	row := 2
+	var write func(emp models.EmployeeData, level int)
+	write = func(emp models.EmployeeData, level int) {
+		reports := tree.Reports(emp.EmployeeNumber)
+		values := []interface{}{emp.Department, emp.Role, emp.EmployeeNumber, emp.FullName(), emp.ManagerEmployeeNumber, len(reports)}
+		for i, value := range values {
+			f.SetCellValue(OrgChartSheet, fmt.Sprintf("%s%d", getExcelColumn(i), row), value)
+		}
+		name := fmt.Sprintf("D%d", row)
+		f.SetCellStyle(OrgChartSheet, name, name, indents[min(level, len(indents)-1)])
+		row++
+		for _, report := range reports {
+			write(report, level+1)
+		}
+	}
+	for _, head := range heads {
+		write(head, 0)
+	}
+
+	f.SetColWidth(OrgChartSheet, "A", "C", 18)
+	f.SetColWidth(OrgChartSheet, "D", "D", 32)
+	f.SetColWidth(OrgChartSheet, "E", "F", 24)
+	return nil
+}
+
+// isRole reports whether role is one of models.Roles
+func isRole(role string) bool {
+	for _, r := range models.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// orgEntry is the organisation columns of a workbook row
+type orgEntry struct {
+	Row            int
+	EmployeeNumber string
+	Name           string
+	Department     string
+	Role           string
+	Manager        string
+	ManagerName    string
+}
+
+// verifyOrganisation checks that every manager reference points to a
+// manager in the same department, that only department heads have no
+// manager, and that the references do not form cycles
+func verifyOrganisation(entries []orgEntry) []Issue {
+	var issues []Issue
+	add := func(e orgEntry, column, format string, args ...interface{}) {
+		issues = append(issues, Issue{Row: e.Row, Column: column, Message: fmt.Sprintf(format, args...)})
+	}
+
+	byNumber := make(map[string]orgEntry)
+	for _, e := range entries {
+		if _, ok := byNumber[e.EmployeeNumber]; !ok {
+			byNumber[e.EmployeeNumber] = e
+		}
+	}
+
+	for _, e := range entries {
+		if e.Manager == "" {
+			if e.Role != models.RoleDepartmentHead {
+				add(e, "ManagerEmployeeNumber", "missing manager of %s", e.Role)
+			}
+			continue
+		}
+		if e.Role == models.RoleDepartmentHead {
+			add(e, "ManagerEmployeeNumber", "department head reports to %s", e.Manager)
+		}
+		manager, ok := byNumber[e.Manager]
+		if !ok {
+			add(e, "ManagerEmployeeNumber", "manager %s is not in the workbook", e.Manager)
+			continue
+		}
+		if manager.Role != models.RoleDepartmentHead && manager.Role != models.RoleTeamLead {
+			add(e, "ManagerEmployeeNumber", "manager %s is %s, not a department head or team lead", e.Manager, manager.Role)
+		}
+		if manager.Department != e.Department {
+			add(e, "ManagerEmployeeNumber", "manager %s is in %s, not %s", e.Manager, manager.Department, e.Department)
+		}
+		if e.ManagerName != manager.Name {
+			add(e, "ManagerName", "%q is not the name of manager %s, %s", e.ManagerName, e.Manager, manager.Name)
+		}
+
+		// Follow the managers up; a chain longer than the workbook is a cycle
+		number := e.Manager
+		for steps := 0; number != "" && steps <= len(entries); steps++ {
+			if number == e.EmployeeNumber {
+				add(e, "ManagerEmployeeNumber", "reports to themselves through %s", e.Manager)
+				break
+			}
+			number = byNumber[number].Manager
+		}
+	}
+	return issues
+}
@@ -0,0 +1,103 @@
+package excel
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"dsb-excel-generator/pkg/models"
+)
+
+// orgTree returns a valid department: a head, a team lead and two staff
+func orgTree() []orgEntry {
+	return []orgEntry{
+		{Row: 2, EmployeeNumber: "E1", Name: "Anne", Department: "IT", Role: models.RoleDepartmentHead},
+		{Row: 3, EmployeeNumber: "E2", Name: "Bo", Department: "IT", Role: models.RoleTeamLead, Manager: "E1", ManagerName: "Anne"},
+		{Row: 4, EmployeeNumber: "E3", Name: "Carl", Department: "IT", Role: models.RoleStaff, Manager: "E2", ManagerName: "Bo"},
+		{Row: 5, EmployeeNumber: "E4", Name: "Dorte", Department: "IT", Role: models.RoleStaff, Manager: "E2", ManagerName: "Bo"},
+	}
+}
+
+func TestVerifyOrganisation(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(entries []orgEntry)
+		want   []string
+	}{
+		{"valid", func(entries []orgEntry) {}, nil},
+		{"unknown manager", func(entries []orgEntry) {
+			entries[2].Manager = "E9"
+		}, []string{"4 ManagerEmployeeNumber: manager E9 is not in the workbook"}},
+		{"missing manager", func(entries []orgEntry) {
+			entries[3].Manager, entries[3].ManagerName = "", ""
+		}, []string{"5 ManagerEmployeeNumber: missing manager of " + models.RoleStaff}},
+		{"cycle between team leads", func(entries []orgEntry) {
+			entries[3].Role = models.RoleTeamLead
+			entries[1].Manager, entries[1].ManagerName = "E4", "Dorte"
+			entries[3].Manager, entries[3].ManagerName = "E2", "Bo"
+		}, []string{
+			"3 ManagerEmployeeNumber: reports to themselves through E4",
+			"5 ManagerEmployeeNumber: reports to themselves through E2",
+		}},
+		{"manager is staff", func(entries []orgEntry) {
+			entries[3].Manager, entries[3].ManagerName = "E3", "Carl"
+		}, []string{"5 ManagerEmployeeNumber: manager E3 is " + models.RoleStaff + ", not a department head or team lead"}},
+		{"manager in another department", func(entries []orgEntry) {
+			entries[3].Department = "HR"
+		}, []string{"5 ManagerEmployeeNumber: manager E2 is in IT, not HR"}},
+		{"wrong manager name", func(entries []orgEntry) {
+			entries[3].ManagerName = "Anne"
+		}, []string{`5 ManagerName: "Anne" is not the name of manager E2, Bo`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := orgTree()
+			tt.change(entries)
+			var got []string
+			for _, issue := range verifyOrganisation(entries) {
+				got = append(got, fmt.Sprintf("%d %s: %s", issue.Row, issue.Column, issue.Message))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got issues\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestPlanOrganisation(t *testing.T) {
+	for _, n := range []int{1, 25, 400} {
+		plan := planOrganisation(n)
+		var entries []orgEntry
+		heads := make(map[string]int)
+		for i, p := range plan {
+			entry := orgEntry{Row: i + 2, EmployeeNumber: fmt.Sprint(i + 1), Department: p.Department, Role: p.Role}
+			if p.Manager > 0 {
+				entry.Manager = fmt.Sprint(p.Manager)
+			}
+			entries = append(entries, entry)
+			if p.Role == models.RoleDepartmentHead {
+				heads[p.Department]++
+			}
+		}
+		for _, issue := range verifyOrganisation(entries) {
+			t.Errorf("%d employees: %s", n, issue)
+		}
+		for department, count := range heads {
+			if count != 1 {
+				t.Errorf("%d employees: %s has %d heads", n, department, count)
+			}
+		}
+		// No team lead has more than teamSize reports
+		reports := make(map[int]int)
+		for _, p := range plan {
+			if p.Role == models.RoleStaff {
+				reports[p.Manager]++
+			}
+		}
+		for manager, count := range reports {
+			if plan[manager-1].Role == models.RoleTeamLead && count > teamSize {
+				t.Errorf("%d employees: team lead %d has %d reports", n, manager, count)
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package excel
+
+import (
+	"fmt"
+	"math"
+
+	"dsb-excel-generator/pkg/models"
+)
+
+// PayScale is a salary scale (lønskala) with the monthly salary of each
+// step (løntrin), starting at step 1
+type PayScale struct {
+	Name  string
+	Steps []float64
+}
+
+// Salary returns the monthly salary of a step, which must be on the scale;
+// checkPayScale ensures every generated step is
+func (s PayScale) Salary(step int) float64 {
+	return s.Steps[step-1]
+}
+
+// DefaultPayScale returns a 50-step scale rising 2.4% per step from
+// 19,000 kr. to about 60,000 kr. a month
+func DefaultPayScale() PayScale {
+	scale := PayScale{Name: "Lønskala 2025"}
+	salary := 19000.0
+	for step := 1; step <= 50; step++ {
+		scale.Steps = append(scale.Steps, math.Round(salary*100)/100)
+		salary *= 1.024
+	}
+	return scale
+}
+
+// StepRange places a department's employees on the scale: they start at
+// step Start and move up one step per YearsPerStep years of seniority,
+// up to step Top
+type StepRange struct {
+	Start        int
+	Top          int
+	YearsPerStep int
+}
+
+// Step returns the step for the years of seniority
+func (r StepRange) Step(seniority int) int {
+	step := r.Start + seniority/r.YearsPerStep
+	if step > r.Top {
+		return r.Top
+	}
+	return step
+}
+
+// DefaultDepartmentSteps returns the step ranges of the generated departments
+func DefaultDepartmentSteps() map[string]StepRange {
+	return map[string]StepRange{
+		"Customer Service": {Start: 11, Top: 19, YearsPerStep: 2},
+		"Logistics":        {Start: 13, Top: 22, YearsPerStep: 2},
+		"Administration":   {Start: 14, Top: 24, YearsPerStep: 2},
+		"Operations":       {Start: 15, Top: 26, YearsPerStep: 2},
+		"Sales":            {Start: 17, Top: 30, YearsPerStep: 2},
+		"Marketing":        {Start: 20, Top: 32, YearsPerStep: 2},
+		"HR":               {Start: 22, Top: 34, YearsPerStep: 3},
+		"Finance":          {Start: 24, Top: 38, YearsPerStep: 3},
+		"IT":               {Start: 26, Top: 42, YearsPerStep: 3},
+		"Legal":            {Start: 30, Top: 45, YearsPerStep: 3},
+	}
+}
+
+// Supplement is a monthly supplement (tillæg) paid on top of the scale salary
+type Supplement struct {
+	Name   string
+	Amount float64
+	// Share is the fraction of eligible employees who receive the supplement
+	Share float64
+	// MinSeniority is the seniority in years required to be eligible
+	MinSeniority int
+	// Role limits the supplement to employees of a role, if set
+	Role string
+}
+
+// DefaultSupplements returns the supplements of the generated data
+func DefaultSupplements() []Supplement {
+	return []Supplement{
+		{Name: "Funktionstillæg", Amount: 1500, Share: 0.25},
+		{Name: "Kvalifikationstillæg", Amount: 1000, Share: 0.3},
+		{Name: "Anciennitetstillæg", Amount: 800, Share: 1, MinSeniority: 10},
+		{Name: "Ledelsestillæg", Amount: 3500, Share: 1, Role: models.RoleTeamLead},
+		{Name: "Chefstillæg", Amount: 7000, Share: 1, Role: models.RoleDepartmentHead},
+	}
+}
+
+// traineeYears is the number of years trainees are in, each on its own
+// step from step 1
+const traineeYears = 4
+
+// checkPayScale checks that every department, and trainees if there are
+// any, are placed within the scale
+func checkPayScale(scale PayScale, steps map[string]StepRange, supplements []Supplement, trainees bool) error {
+	if len(scale.Steps) == 0 {
+		return fmt.Errorf("pay scale %q has no steps", scale.Name)
+	}
+	if trainees && len(scale.Steps) < traineeYears {
+		return fmt.Errorf("pay scale %q has %d steps, trainees need %d", scale.Name, len(scale.Steps), traineeYears)
+	}
+	for _, department := range departments {
+		r, ok := steps[department]
+		if !ok {
+			return fmt.Errorf("no pay scale steps for department %s", department)
+		}
+		if r.Start < 1 || r.Top > len(scale.Steps) || r.Start > r.Top {
+			return fmt.Errorf("steps %d-%d of department %s are outside pay scale %q with %d steps",
+				r.Start, r.Top, department, scale.Name, len(scale.Steps))
+		}
+		if r.YearsPerStep < 1 {
+			return fmt.Errorf("years per step of department %s must be positive, got %d", department, r.YearsPerStep)
+		}
+	}
+	for _, s := range supplements {
+		if s.Amount < 0 || s.Share < 0 || s.Share > 1 {
+			return fmt.Errorf("supplement %s needs a non-negative amount and a share between 0 and 1", s.Name)
+		}
+		if s.Role != "" && !isRole(s.Role) {
+			return fmt.Errorf("supplement %s is for unknown role %q", s.Name, s.Role)
+		}
+	}
+	return nil
+}
+
+// pickSupplements draws the supplements of an employee and returns their total
+func pickSupplements(supplements []Supplement, seniority int, role string) float64 {
+	total := 0.0
+	for _, s := range supplements {
+		if s.Role != "" && s.Role != role {
+			continue
+		}
+		if seniority >= s.MinSeniority && rng.Float64() < s.Share {
+			total += s.Amount
+		}
+	}
+	return total
+}
@@ -0,0 +1,69 @@
+package excel
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"dsb-excel-generator/pkg/models"
+)
+
+// uniformSteps places every department on the same steps
+func uniformSteps(r StepRange) map[string]StepRange {
+	steps := make(map[string]StepRange)
+	for _, department := range departments {
+		steps[department] = r
+	}
+	return steps
+}
+
+func TestCheckPayScale(t *testing.T) {
+	short := PayScale{Name: "Short", Steps: []float64{20000, 21000, 22000}}
+	tests := []struct {
+		name     string
+		scale    PayScale
+		steps    map[string]StepRange
+		trainees bool
+		want     string
+	}{
+		{"default", DefaultPayScale(), DefaultDepartmentSteps(), true, ""},
+		{"short scale without trainees", short, uniformSteps(StepRange{1, 3, 2}), false, ""},
+		{"short scale with trainees", short, uniformSteps(StepRange{1, 3, 2}), true, "trainees need 4"},
+		{"default steps on a short scale", short, DefaultDepartmentSteps(), false, "outside pay scale"},
+		{"top above the scale", short, uniformSteps(StepRange{1, 4, 2}), false, "outside pay scale"},
+		{"start below step 1", short, uniformSteps(StepRange{0, 3, 2}), false, "outside pay scale"},
+		{"no years per step", short, uniformSteps(StepRange{1, 3, 0}), false, "must be positive"},
+		{"empty scale", PayScale{Name: "Empty"}, uniformSteps(StepRange{1, 1, 1}), false, "has no steps"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPayScale(tt.scale, tt.steps, DefaultSupplements(), tt.trainees)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateShortPayScale(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Rows = 50
+	opts.Seed = 1
+	opts.PayScale = PayScale{Name: "Short", Steps: []float64{20000, 21000, 22000}}
+	opts.DepartmentSteps = uniformSteps(StepRange{1, 3, 2})
+	filename := filepath.Join(t.TempDir(), "short.xlsx")
+	if err := Generate(filename, opts); err == nil || !strings.Contains(err.Error(), "trainees need") {
+		t.Errorf("got error %v, want trainee steps outside the scale", err)
+	}
+
+	opts.EmploymentTypes = []Weighted{{models.EmploymentMonthly, 1}}
+	if err := Generate(filename, opts); err != nil {
+		t.Errorf("Generate without trainees: %v", err)
+	}
+}
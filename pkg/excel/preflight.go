@@ -0,0 +1,120 @@
+package excel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"dsb-excel-generator/pkg/models"
+)
+
+// PreflightRules are the bounds of the pre-flight check
+type PreflightRules struct {
+	// MinIncrease and MaxIncrease bound the total increase from BaseSalary
+	// to NewBaseSalary, in percent
+	MinIncrease float64
+	MaxIncrease float64
+	// GrossRatioTolerance is how far, in percent, an employee's ratio of
+	// GrossSalary to BaseSalary may be from the median ratio of their department
+	GrossRatioTolerance float64
+	// Campaign, if it has a year, flags effective dates outside the
+	// campaign year, whose back pay is cut short or missing
+	Campaign models.Campaign
+}
+
+// DefaultPreflightRules returns bounds that the generated data stays within
+func DefaultPreflightRules() PreflightRules {
+	return PreflightRules{MinIncrease: 0, MaxIncrease: 10, GrossRatioTolerance: 10, Campaign: models.DefaultCampaign()}
+}
+
+// Preflight flags suspicious rows before letters go out: increases outside
+// the bounds, new base salaries below the current, gross/base ratios outside
+// the department norm, effective dates outside the campaign year, missing or
+// duplicate CPR and employee numbers, and case numbers shared by several
+// employees. Issues give the sheet row the employee was
+// read from, or for employees not read from a workbook, their position
+// counted from row 2.
+func Preflight(employees []models.EmployeeData, rules PreflightRules) []Issue {
+	row := func(i int) int {
+		if employees[i].Row > 0 {
+			return employees[i].Row
+		}
+		return i + 2
+	}
+	var issues []Issue
+	add := func(i int, column, format string, args ...interface{}) {
+		message := fmt.Sprintf(format, args...)
+		if number := employees[i].EmployeeNumber; number != "" {
+			message = number + ": " + message
+		}
+		issues = append(issues, Issue{Row: row(i), Column: column, Message: message})
+	}
+
+	// Department norms: the median gross/base ratio
+	ratios := make(map[string][]float64)
+	for _, emp := range employees {
+		if base := emp.Amount("BaseSalary"); base > 0 {
+			ratios[emp.Department] = append(ratios[emp.Department], emp.Amount("GrossSalary")/base)
+		}
+	}
+	norms := make(map[string]float64)
+	for department, r := range ratios {
+		sort.Float64s(r)
+		norms[department] = r[len(r)/2]
+	}
+
+	seenCPR := make(map[string]int)
+	seenEmployee := make(map[string]int)
+	caseOwners := make(map[string]int)
+	for i, emp := range employees {
+		base, newBase := emp.Amount("BaseSalary"), emp.Amount("NewBaseSalary")
+
+		if newBase < base {
+			add(i, "NewBaseSalary", "new base salary %.2f kr. is lower than the current %.2f kr.", newBase, base)
+		}
+		if increase := emp.TotalIncrease(); increase < rules.MinIncrease || increase > rules.MaxIncrease {
+			add(i, "NewBaseSalary", "increase of %.2f%% is outside %g-%g%%", increase, rules.MinIncrease, rules.MaxIncrease)
+		}
+		if norm := norms[emp.Department]; base > 0 && norm > 0 {
+			ratio := emp.Amount("GrossSalary") / base
+			if deviation := (ratio/norm - 1) * 100; math.Abs(deviation) > rules.GrossRatioTolerance {
+				add(i, "GrossSalary", "gross salary is %.2f times base salary, %.1f%% from the %s norm of %.2f",
+					ratio, deviation, emp.Department, norm)
+			}
+		}
+		if year := rules.Campaign.Year; year > 0 && !emp.EffectiveDate.IsZero() {
+			switch effective := emp.EffectiveDate; {
+			case effective.Year() < year:
+				add(i, "EffectiveDate", "effective date %s is before the campaign year %d; back pay only covers %d months from 1. januar",
+					effective, year, rules.Campaign.MaxRetroactiveMonths())
+			case effective.Year() > year:
+				add(i, "EffectiveDate", "effective date %s is after the campaign year %d", effective, year)
+			}
+		}
+
+		if strings.TrimSpace(emp.CPR) == "" {
+			add(i, "CPR", "CPR number is missing")
+		} else if first, ok := seenCPR[emp.CPR]; ok {
+			add(i, "CPR", "CPR number also used in row %d", first)
+		} else {
+			seenCPR[emp.CPR] = row(i)
+		}
+		if strings.TrimSpace(emp.EmployeeNumber) == "" {
+			add(i, "EmployeeNumber", "employee number is missing")
+		} else if first, ok := seenEmployee[emp.EmployeeNumber]; ok {
+			add(i, "EmployeeNumber", "employee number also used in row %d", first)
+		} else {
+			seenEmployee[emp.EmployeeNumber] = row(i)
+		}
+		if emp.CaseNumber == "" {
+			continue
+		}
+		if owner, ok := caseOwners[emp.CaseNumber]; !ok {
+			caseOwners[emp.CaseNumber] = i
+		} else if employees[owner].EmployeeNumber != emp.EmployeeNumber {
+			add(i, "CaseNumber", "case number %s is also used for %s in row %d", emp.CaseNumber, employees[owner].EmployeeNumber, row(owner))
+		}
+	}
+	return issues
+}
@@ -0,0 +1,145 @@
+package excel
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestPreflightRowsAfterBlankRows(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Rows = 5
+	opts.Seed = 1
+	filename := filepath.Join(t.TempDir(), "blank-rows.xlsx")
+	if err := Generate(filename, opts); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	// Insert blank rows before the third and fifth employees, which moves
+	// the last employee from row 6 to row 8, and give it the CPR and case
+	// number of the first
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range []int{3, 6} {
+		if err := f.InsertRows(SheetName, row, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, header := range []string{"CPR", "CaseNumber"} {
+		col := getExcelColumn(headerIndex(header))
+		value, err := f.GetCellValue(SheetName, col+"2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.SetCellValue(SheetName, col+"8", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	employees, err := ReadEmployees(filename)
+	if err != nil {
+		t.Fatalf("ReadEmployees: %v", err)
+	}
+	var rows []int
+	for _, emp := range employees {
+		rows = append(rows, emp.Row)
+	}
+	if got, want := fmt.Sprint(rows), "[2 4 5 7 8]"; got != want {
+		t.Fatalf("employee rows %s, want %s", got, want)
+	}
+
+	want := map[string]string{
+		"CPR":        "also used in row 2",
+		"CaseNumber": "in row 2",
+	}
+	for _, issue := range Preflight(employees, DefaultPreflightRules()) {
+		suffix, ok := want[issue.Column]
+		if !ok {
+			continue
+		}
+		if issue.Row != 8 || !strings.HasSuffix(issue.Message, suffix) {
+			t.Errorf("got %s, want row 8 ending in %q", issue, suffix)
+		}
+		delete(want, issue.Column)
+	}
+	for column := range want {
+		t.Errorf("no %s issue", column)
+	}
+}
+
+func TestPreflightEffectiveDatesOutsideCampaign(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Rows = 3
+	opts.Seed = 1
+	filename := filepath.Join(t.TempDir(), "effective.xlsx")
+	if err := Generate(filename, opts); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	employees, err := ReadEmployees(filename)
+	if err != nil {
+		t.Fatalf("ReadEmployees: %v", err)
+	}
+	employees[0].EffectiveDate = models.NewDate(2024, time.March, 1)
+	employees[2].EffectiveDate = models.NewDate(2026, time.March, 1)
+
+	var got []string
+	for _, issue := range Preflight(employees, DefaultPreflightRules()) {
+		if issue.Column == "EffectiveDate" {
+			got = append(got, fmt.Sprintf("%d: %s", issue.Row, issue.Message))
+		}
+	}
+	want := []string{
+		fmt.Sprintf("2: %s: effective date 2024-03-01 is before the campaign year 2025; back pay only covers 5 months from 1. januar", employees[0].EmployeeNumber),
+		fmt.Sprintf("4: %s: effective date 2026-03-01 is after the campaign year 2025", employees[2].EmployeeNumber),
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got issues\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPreflightMissingNumbers(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Rows = 4
+	opts.Seed = 1
+	filename := filepath.Join(t.TempDir(), "missing.xlsx")
+	if err := Generate(filename, opts); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	employees, err := ReadEmployees(filename)
+	if err != nil {
+		t.Fatalf("ReadEmployees: %v", err)
+	}
+	// Blank values are missing, not duplicates of each other
+	employees[1].CPR = ""
+	employees[2].CPR = " "
+	employees[2].EmployeeNumber = ""
+	employees[3].EmployeeNumber = ""
+
+	var got []string
+	for _, issue := range Preflight(employees, DefaultPreflightRules()) {
+		if issue.Column == "CPR" || issue.Column == "EmployeeNumber" {
+			got = append(got, fmt.Sprintf("%d %s: %s", issue.Row, issue.Column, issue.Message))
+		}
+	}
+	want := []string{
+		fmt.Sprintf("3 CPR: %s: CPR number is missing", employees[1].EmployeeNumber),
+		"4 CPR: CPR number is missing",
+		"4 EmployeeNumber: employee number is missing",
+		"5 EmployeeNumber: employee number is missing",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got issues\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,62 @@
+package excel
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// EditableHeaders are the columns left unlocked in a protected workbook:
+// the decisions HR fills in. Every other column, and every other sheet, is
+// locked.
+var EditableHeaders = []string{"PercentageIncrease", "AdditionalNotes", "ApprovalStatus", "ApprovedBy"}
+
+// protectWorkbook unlocks the editable columns of the employee rows and
+// protects every sheet, and the workbook structure, with the password. An
+// empty password protects without one, which guards against accidental
+// edits only. Filters and column widths can still be used on the employee
+// sheet.
+func protectWorkbook(f *excelize.File, rows int, password string) error {
+	unlocked, err := f.NewStyle(&excelize.Style{Protection: &excelize.Protection{Locked: false}})
+	if err != nil {
+		return fmt.Errorf("error creating unlocked style: %v", err)
+	}
+	unlockedDecimal, err := f.NewStyle(&excelize.Style{NumFmt: 2, Protection: &excelize.Protection{Locked: false}})
+	if err != nil {
+		return fmt.Errorf("error creating unlocked style: %v", err)
+	}
+	for _, header := range EditableHeaders {
+		style := unlocked
+		if isNumericHeader(header) {
+			style = unlockedDecimal
+		}
+		col := getExcelColumn(headerIndex(header))
+		if err := f.SetCellStyle(SheetName, col+"2", fmt.Sprintf("%s%d", col, rows+1), style); err != nil {
+			return fmt.Errorf("error unlocking %s: %v", header, err)
+		}
+	}
+
+	for _, sheet := range f.GetSheetList() {
+		opts := &excelize.SheetProtectionOptions{
+			Password:            password,
+			AlgorithmName:       "SHA-512",
+			SelectLockedCells:   true,
+			SelectUnlockedCells: true,
+		}
+		if sheet == SheetName {
+			opts.AutoFilter = true
+			opts.FormatColumns = true
+		}
+		if err := f.ProtectSheet(sheet, opts); err != nil {
+			return fmt.Errorf("error protecting sheet %s: %v", sheet, err)
+		}
+	}
+
+	if err := f.ProtectWorkbook(&excelize.WorkbookProtectionOptions{
+		Password:      password,
+		LockStructure: true,
+	}); err != nil {
+		return fmt.Errorf("error protecting workbook: %v", err)
+	}
+	return nil
+}
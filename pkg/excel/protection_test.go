@@ -0,0 +1,85 @@
+package excel
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestProtectWorkbook(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Rows = 6
+	opts.Seed = 1
+	opts.Protect = true
+	opts.Password = "secret"
+	filename := filepath.Join(t.TempDir(), "protected.xlsx")
+	if err := Generate(filename, opts); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// Only the decisions HR fills in are unlocked, on the employee rows
+	editable := []string{"PercentageIncrease", "AdditionalNotes", "ApprovalStatus", "ApprovedBy"}
+	for row := 2; row <= opts.Rows+2; row++ {
+		for i, header := range Headers {
+			cell := fmt.Sprintf("%s%d", getExcelColumn(i), row)
+			id, err := f.GetCellStyle(SheetName, cell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			style, err := f.GetStyle(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			locked := style.Protection == nil || style.Protection.Locked
+			if want := !slices.Contains(editable, header) || row > opts.Rows+1; locked != want {
+				t.Errorf("%s (%s) locked: %v, want %v", cell, header, locked, want)
+			}
+		}
+	}
+
+	// Every sheet is protected with the password, and so is the structure
+	sheets := f.GetSheetList()
+	for i, sheet := range sheets {
+		var worksheet struct {
+			Protection *struct {
+				Sheet      bool   `xml:"sheet,attr"`
+				HashValue  string `xml:"hashValue,attr"`
+				AutoFilter *bool  `xml:"autoFilter,attr"`
+			} `xml:"sheetProtection"`
+		}
+		if err := xml.Unmarshal(readPart(t, filename, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)), &worksheet); err != nil {
+			t.Fatal(err)
+		}
+		p := worksheet.Protection
+		if p == nil || !p.Sheet || p.HashValue == "" {
+			t.Errorf("sheet %s is not protected with a password", sheet)
+			continue
+		}
+		// Filtering stays allowed on the employee sheet only
+		filterAllowed := p.AutoFilter != nil && !*p.AutoFilter
+		if filterAllowed != (sheet == SheetName) {
+			t.Errorf("sheet %s allows filters: %v", sheet, filterAllowed)
+		}
+	}
+	var workbook struct {
+		Protection *struct {
+			LockStructure bool   `xml:"lockStructure,attr"`
+			HashValue     string `xml:"workbookHashValue,attr"`
+		} `xml:"workbookProtection"`
+	}
+	if err := xml.Unmarshal(readPart(t, filename, "xl/workbook.xml"), &workbook); err != nil {
+		t.Fatal(err)
+	}
+	if p := workbook.Protection; p == nil || !p.LockStructure || p.HashValue == "" {
+		t.Errorf("workbook structure is not locked with a password: %+v", p)
+	}
+}
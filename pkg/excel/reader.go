@@ -0,0 +1,85 @@
+package excel
+
+import (
+	"fmt"
+	"strconv"
+
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ReadEmployees reads the employee rows from a generated workbook.
+// Columns are matched by header name, so their order does not matter
+// and unknown columns are ignored. Each employee records its sheet row.
+func ReadEmployees(filename string) ([]models.EmployeeData, error) {
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Excel file: %v", err)
+	}
+	defer f.Close()
+
+	rows, err := readRows(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("sheet %s has no header row", SheetName)
+	}
+
+	headers := rows[0]
+	employees := make([]models.EmployeeData, 0, len(rows)-1)
+	for r, row := range rows[1:] {
+		if len(row) == 0 {
+			continue // Skip blank rows
+		}
+
+		emp := models.EmployeeData{Row: r + 2}
+		for i, value := range row {
+			if i < len(headers) {
+				emp.SetField(headers[i], value)
+			}
+		}
+		employees = append(employees, emp)
+	}
+
+	return employees, nil
+}
+
+// readRows returns the cell values of the employee sheet. Formula cells are
+// evaluated, so derived columns are correct even if the workbook was saved
+// by a program that does not recalculate; if a formula cannot be evaluated
+// its cached result is used. Calculated amounts are formatted with two
+// decimals, as the generator writes them.
+func readRows(f *excelize.File) ([][]string, error) {
+	rows, err := f.GetRows(SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows: %v", err)
+	}
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	headers := rows[0]
+	for r := 1; r < len(rows); r++ {
+		for i, header := range headers {
+			cell := fmt.Sprintf("%s%d", getExcelColumn(i), r+1)
+			formula, err := f.GetCellFormula(SheetName, cell)
+			if err != nil || formula == "" {
+				continue
+			}
+			value, err := f.CalcCellValue(SheetName, cell, excelize.Options{RawCellValue: true})
+			if err != nil {
+				continue
+			}
+			if number, err := strconv.ParseFloat(value, 64); err == nil && isNumericHeader(header) && !wholeNumberHeaders[header] {
+				value = fmt.Sprintf("%.2f", number)
+			}
+			for len(rows[r]) <= i {
+				rows[r] = append(rows[r], "")
+			}
+			rows[r][i] = value
+		}
+	}
+	return rows, nil
+}
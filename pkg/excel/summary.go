@@ -0,0 +1,181 @@
+package excel
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Summary sheets added by Generate. Their cells are formulas over whole
+// columns of the employee sheet, so they stay correct when rows are edited,
+// added or removed.
+const (
+	LetterSummarySheet     = "Letter Summary"
+	DepartmentSummarySheet = "Department Summary"
+	DistributionSheet      = "Increase Distribution"
+)
+
+// distributionBins are the lower bounds of the PercentageIncrease histogram
+// bins; the last bin has no upper bound
+var distributionBins = []float64{0, 0.5, 1, 1.5, 2, 2.5, 3, 3.5, 4, 4.5, 5}
+
+// column returns the whole-column reference of a header on the employee
+// sheet, e.g. Sheet1!$AG:$AG
+func column(header string) (string, error) {
+	i := headerIndex(header)
+	if i < 0 {
+		return "", fmt.Errorf("unknown header %s", header)
+	}
+	col := getExcelColumn(i)
+	return fmt.Sprintf("%s!$%s:$%s", SheetName, col, col), nil
+}
+
+// cellName returns the name of a cell from 0-based column and 1-based row
+func cellName(col, row int) string {
+	return fmt.Sprintf("%s%d", getExcelColumn(col), row)
+}
+
+// writeSummarySheets adds the letter, department and distribution summaries.
+// Text criteria are written as "="&cell so they match whole values only;
+// Confidential must not also count Strictly Confidential.
+func writeSummarySheets(f *excelize.File) error {
+	// The employee columns the formulas refer to
+	columns := make(map[string]string)
+	for _, header := range []string{"LetterType", "SecurityLevel", "Department", "BaseSalary", "NewBaseSalary",
+		"GeneralAdjustment", "IndividualAdjustment", "PercentageIncrease"} {
+		ref, err := column(header)
+		if err != nil {
+			return fmt.Errorf("error writing summary sheets: %v", err)
+		}
+		columns[header] = ref
+	}
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("error creating summary style: %v", err)
+	}
+	amount, err := f.NewStyle(&excelize.Style{NumFmt: 4}) // #,##0.00
+	if err != nil {
+		return fmt.Errorf("error creating summary style: %v", err)
+	}
+	boldAmount, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, NumFmt: 4})
+	if err != nil {
+		return fmt.Errorf("error creating summary style: %v", err)
+	}
+	percent, err := f.NewStyle(&excelize.Style{NumFmt: 10}) // 0.00%
+	if err != nil {
+		return fmt.Errorf("error creating summary style: %v", err)
+	}
+
+	for _, sheet := range []string{LetterSummarySheet, DepartmentSummarySheet, DistributionSheet} {
+		if _, err := f.NewSheet(sheet); err != nil {
+			return fmt.Errorf("error creating sheet %s: %v", sheet, err)
+		}
+	}
+
+	// Letters: counts by letter type and security level
+	sheet := LetterSummarySheet
+	f.SetCellValue(sheet, "A1", "LetterType")
+	for j, level := range SecurityLevels {
+		f.SetCellValue(sheet, cellName(j+1, 1), level)
+	}
+	totalCol := len(SecurityLevels) + 1
+	f.SetCellValue(sheet, cellName(totalCol, 1), "Total")
+	for i, letterType := range LetterTypes {
+		row := i + 2
+		f.SetCellValue(sheet, cellName(0, row), letterType)
+		for j := range SecurityLevels {
+			f.SetCellFormula(sheet, cellName(j+1, row), fmt.Sprintf(`COUNTIFS(%s,"="&$A%d,%s,"="&%s$1)`,
+				columns["LetterType"], row, columns["SecurityLevel"], getExcelColumn(j+1)))
+		}
+		f.SetCellFormula(sheet, cellName(totalCol, row), fmt.Sprintf("SUM(B%d:%s)", row, cellName(totalCol-1, row)))
+	}
+	totalRow := len(LetterTypes) + 2
+	f.SetCellValue(sheet, cellName(0, totalRow), "Total")
+	for j := 1; j <= totalCol; j++ {
+		col := getExcelColumn(j)
+		f.SetCellFormula(sheet, cellName(j, totalRow), fmt.Sprintf("SUM(%s2:%s%d)", col, col, totalRow-1))
+	}
+	f.SetCellStyle(sheet, "A1", cellName(totalCol, 1), bold)
+	f.SetCellStyle(sheet, cellName(0, totalRow), cellName(totalCol, totalRow), bold)
+	f.SetColWidth(sheet, "A", getExcelColumn(totalCol), 22)
+
+	// Departments: payroll totals and average increases
+	sheet = DepartmentSummarySheet
+	headers := []string{"Department", "Employees", "Payroll", "NewPayroll", "GeneralAdjustments",
+		"IndividualAdjustments", "AverageIncrease", "AverageIndividualIncrease"}
+	for j, header := range headers {
+		f.SetCellValue(sheet, cellName(j, 1), header)
+	}
+	sums := map[string]string{"C": "BaseSalary", "D": "NewBaseSalary", "E": "GeneralAdjustment", "F": "IndividualAdjustment"}
+	for i, department := range departments {
+		row := i + 2
+		f.SetCellValue(sheet, cellName(0, row), department)
+		f.SetCellFormula(sheet, fmt.Sprintf("B%d", row), fmt.Sprintf(`COUNTIF(%s,"="&$A%d)`, columns["Department"], row))
+		for _, col := range []string{"C", "D", "E", "F"} {
+			f.SetCellFormula(sheet, fmt.Sprintf("%s%d", col, row), fmt.Sprintf(`SUMIF(%s,"="&$A%d,%s)`,
+				columns["Department"], row, columns[sums[col]]))
+		}
+		// The payroll's increase, and the mean of the employees' individual percentages
+		f.SetCellFormula(sheet, fmt.Sprintf("G%d", row), fmt.Sprintf("IF(C%d=0,0,D%d/C%d-1)", row, row, row))
+		f.SetCellFormula(sheet, fmt.Sprintf("H%d", row), fmt.Sprintf(`IF(B%d=0,0,AVERAGEIF(%s,"="&$A%d,%s)/100)`,
+			row, columns["Department"], row, columns["PercentageIncrease"]))
+	}
+	totalRow = len(departments) + 2
+	last := totalRow - 1
+	f.SetCellValue(sheet, fmt.Sprintf("A%d", totalRow), "Total")
+	for _, col := range []string{"B", "C", "D", "E", "F"} {
+		f.SetCellFormula(sheet, fmt.Sprintf("%s%d", col, totalRow), fmt.Sprintf("SUM(%s2:%s%d)", col, col, last))
+	}
+	f.SetCellFormula(sheet, fmt.Sprintf("G%d", totalRow), fmt.Sprintf("IF(C%d=0,0,D%d/C%d-1)", totalRow, totalRow, totalRow))
+	f.SetCellFormula(sheet, fmt.Sprintf("H%d", totalRow), fmt.Sprintf("IF(B%d=0,0,AVERAGE(%s)/100)", totalRow, columns["PercentageIncrease"]))
+	f.SetCellStyle(sheet, "C2", fmt.Sprintf("F%d", totalRow), amount)
+	f.SetCellStyle(sheet, "G2", fmt.Sprintf("H%d", totalRow), percent)
+	f.SetCellStyle(sheet, "A1", "H1", bold)
+	f.SetCellStyle(sheet, fmt.Sprintf("A%d", totalRow), fmt.Sprintf("B%d", totalRow), bold)
+	f.SetCellStyle(sheet, fmt.Sprintf("C%d", totalRow), fmt.Sprintf("F%d", totalRow), boldAmount)
+	f.SetColWidth(sheet, "A", "A", 20)
+	f.SetColWidth(sheet, "B", "H", 18)
+
+	// Distribution: a histogram of the individual percentages with a chart
+	sheet = DistributionSheet
+	for j, header := range []string{"PercentageIncrease", "From", "To", "Employees", "Share"} {
+		f.SetCellValue(sheet, cellName(j, 1), header)
+	}
+	increases := columns["PercentageIncrease"]
+	for i, from := range distributionBins {
+		row := i + 2
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), from)
+		if i+1 < len(distributionBins) {
+			to := distributionBins[i+1]
+			f.SetCellValue(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("%g-%g%%", from, to))
+			f.SetCellValue(sheet, fmt.Sprintf("C%d", row), to)
+			f.SetCellFormula(sheet, fmt.Sprintf("D%d", row), fmt.Sprintf(`COUNTIFS(%s,">="&B%d,%s,"<"&C%d)`, increases, row, increases, row))
+		} else {
+			f.SetCellValue(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("%g%% or more", from))
+			f.SetCellFormula(sheet, fmt.Sprintf("D%d", row), fmt.Sprintf(`COUNTIF(%s,">="&B%d)`, increases, row))
+		}
+	}
+	last = len(distributionBins) + 1
+	for row := 2; row <= last; row++ {
+		f.SetCellFormula(sheet, fmt.Sprintf("E%d", row), fmt.Sprintf("IF(SUM($D$2:$D$%d)=0,0,D%d/SUM($D$2:$D$%d))", last, row, last))
+	}
+	f.SetCellStyle(sheet, "E2", fmt.Sprintf("E%d", last), percent)
+	f.SetCellStyle(sheet, "A1", "E1", bold)
+	f.SetColWidth(sheet, "A", "A", 20)
+	f.SetColWidth(sheet, "B", "E", 12)
+
+	if err := f.AddChart(sheet, "G2", &excelize.Chart{
+		Type: excelize.Col,
+		Series: []excelize.ChartSeries{{
+			Name:       fmt.Sprintf("'%s'!$D$1", sheet),
+			Categories: fmt.Sprintf("'%s'!$A$2:$A$%d", sheet, last),
+			Values:     fmt.Sprintf("'%s'!$D$2:$D$%d", sheet, last),
+		}},
+		Title:  []excelize.RichTextRun{{Text: "Employees by individual increase"}},
+		Legend: excelize.ChartLegend{Position: "none"},
+	}); err != nil {
+		return fmt.Errorf("error adding distribution chart: %v", err)
+	}
+	return nil
+}
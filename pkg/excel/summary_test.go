@@ -0,0 +1,121 @@
+package excel
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestColumn(t *testing.T) {
+	ref, err := column("Department")
+	if err != nil || ref != "Sheet1!$E:$E" {
+		t.Errorf("column(Department) = %q, %v, want Sheet1!$E:$E", ref, err)
+	}
+	if _, err := column("Departmnet"); err == nil {
+		t.Error("column(Departmnet) succeeded for an unknown header")
+	}
+}
+
+func TestSummaryFormulas(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Rows = 40
+	opts.Seed = 1
+	filename := filepath.Join(t.TempDir(), "summary.xlsx")
+	if err := Generate(filename, opts); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	employees, err := ReadEmployees(filename)
+	if err != nil {
+		t.Fatalf("ReadEmployees: %v", err)
+	}
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	check := func(sheet, cell string, want float64) {
+		t.Helper()
+		value, err := f.CalcCellValue(sheet, cell, excelize.Options{RawCellValue: true})
+		if err != nil {
+			t.Errorf("%s!%s: %v", sheet, cell, err)
+			return
+		}
+		got, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.Abs(got-want) > 0.005 {
+			t.Errorf("%s!%s = %q, want %.2f", sheet, cell, value, want)
+		}
+	}
+	// Whole-column formulas take about a second each to evaluate, so the
+	// test checks one row of each summary: the busiest one
+
+	// Letters of the most common type by security level, where Confidential
+	// must not count Strictly Confidential
+	letters := make(map[string]int)
+	for _, emp := range employees {
+		letters[emp.LetterType]++
+	}
+	row := 2 + busiest(LetterTypes, letters)
+	for j, level := range SecurityLevels {
+		count := 0
+		for _, emp := range employees {
+			if emp.LetterType == LetterTypes[row-2] && emp.SecurityLevel == level {
+				count++
+			}
+		}
+		check(LetterSummarySheet, cellName(j+1, row), float64(count))
+	}
+
+	// Employees and payroll of the largest department
+	employeeCounts := make(map[string]int)
+	for _, emp := range employees {
+		employeeCounts[emp.Department]++
+	}
+	row = 2 + busiest(departments, employeeCounts)
+	payroll, newPayroll := 0.0, 0.0
+	for _, emp := range employees {
+		if emp.Department == departments[row-2] {
+			payroll += emp.Amount("BaseSalary")
+			newPayroll += emp.Amount("NewBaseSalary")
+		}
+	}
+	check(DepartmentSummarySheet, fmt.Sprintf("B%d", row), float64(employeeCounts[departments[row-2]]))
+	check(DepartmentSummarySheet, fmt.Sprintf("C%d", row), payroll)
+	check(DepartmentSummarySheet, fmt.Sprintf("D%d", row), newPayroll)
+
+	// Employees in the fullest increase bin and in the open last bin
+	bins := make([]int, len(distributionBins))
+	for _, emp := range employees {
+		increase := emp.Amount("PercentageIncrease")
+		for i := len(distributionBins) - 1; i >= 0; i-- {
+			if increase >= distributionBins[i] {
+				bins[i]++
+				break
+			}
+		}
+	}
+	fullest := 0
+	for i, count := range bins {
+		if count > bins[fullest] {
+			fullest = i
+		}
+	}
+	last := len(distributionBins) - 1
+	check(DistributionSheet, fmt.Sprintf("D%d", fullest+2), float64(bins[fullest]))
+	check(DistributionSheet, fmt.Sprintf("D%d", last+2), float64(bins[last]))
+}
+
+// busiest returns the index of the value with the highest count
+func busiest(values []string, counts map[string]int) int {
+	best := 0
+	for i, value := range values {
+		if counts[value] > counts[values[best]] {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,150 @@
+package excel
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// TableName is the Excel table holding the employee rows on SheetName
+const TableName = "Employees"
+
+// dropdown is a column whose cells are picked from a list
+type dropdown struct {
+	Header string
+	Values []string
+}
+
+// dropdowns lists the columns edited with a dropdown; Verify also checks
+// their values
+var dropdowns = []dropdown{
+	{"Department", departments},
+	{"LetterType", LetterTypes},
+	{"DocumentType", documentTypes},
+	{"SecurityLevel", SecurityLevels},
+	{"ApprovalStatus", models.ApprovalStatuses},
+}
+
+// allows reports whether value is one of the dropdown's values
+func (d dropdown) allows(value string) bool {
+	for _, v := range d.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// amountHeaders are the salary columns, in kr. per month, that must be
+// between 0 and maxAmount
+var amountHeaders = []string{
+	"BaseSalary", "Supplements", "NewBaseSalary", "FullTimeBaseSalary", "NewFullTimeBaseSalary",
+	"GrossSalary", "NewGrossSalary", "GeneralAdjustment", "IndividualAdjustment", "BackPay",
+}
+
+const maxAmount = 1000000
+
+// formatEmployeeSheet turns the employee rows into an Excel table with
+// filters and a frozen header row, sizes the columns to their contents and
+// adds data validation, so values edited by hand stay valid. Validation
+// covers every row the generator can write, so it also applies to rows
+// added below the generated ones.
+func formatEmployeeSheet(f *excelize.File, employees []models.EmployeeData) error {
+	last := getExcelColumn(len(Headers) - 1)
+	if err := f.AddTable(SheetName, &excelize.Table{
+		Range:     fmt.Sprintf("A1:%s%d", last, len(employees)+1),
+		Name:      TableName,
+		StyleName: "TableStyleMedium2",
+	}); err != nil {
+		return fmt.Errorf("error adding table: %v", err)
+	}
+
+	if err := f.SetPanes(SheetName, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+		Selection:   []excelize.Selection{{SQRef: "A2", ActiveCell: "A2", Pane: "bottomLeft"}},
+	}); err != nil {
+		return fmt.Errorf("error freezing header row: %v", err)
+	}
+
+	setColumnWidths(f, employees)
+
+	rows := func(header string) string {
+		col := getExcelColumn(headerIndex(header))
+		return fmt.Sprintf("%s2:%s%d", col, col, maxRows+1)
+	}
+	for _, d := range dropdowns {
+		dv := excelize.NewDataValidation(true)
+		dv.Sqref = rows(d.Header)
+		if err := dv.SetDropList(d.Values); err != nil {
+			return fmt.Errorf("error adding %s dropdown: %v", d.Header, err)
+		}
+		dv.SetError(excelize.DataValidationErrorStyleStop, "Invalid "+d.Header, "Pick a value from the list.")
+		if err := f.AddDataValidation(SheetName, dv); err != nil {
+			return fmt.Errorf("error adding %s dropdown: %v", d.Header, err)
+		}
+	}
+
+	amounts := excelize.NewDataValidation(true)
+	for _, header := range amountHeaders {
+		amounts.SetSqref(rows(header))
+	}
+	if err := amounts.SetRange(0, maxAmount, excelize.DataValidationTypeDecimal, excelize.DataValidationOperatorBetween); err != nil {
+		return fmt.Errorf("error adding amount validation: %v", err)
+	}
+	amounts.SetError(excelize.DataValidationErrorStyleStop, "Invalid amount",
+		fmt.Sprintf("Enter an amount in kr. per month between 0 and %d.", maxAmount))
+	if err := f.AddDataValidation(SheetName, amounts); err != nil {
+		return fmt.Errorf("error adding amount validation: %v", err)
+	}
+
+	percentage := excelize.NewDataValidation(true)
+	percentage.Sqref = rows("PercentageIncrease")
+	if err := percentage.SetRange(0, 100, excelize.DataValidationTypeDecimal, excelize.DataValidationOperatorBetween); err != nil {
+		return fmt.Errorf("error adding percentage validation: %v", err)
+	}
+	percentage.SetError(excelize.DataValidationErrorStyleStop, "Invalid percentage",
+		"Enter the individual increase in percent, between 0 and 100.")
+	if err := f.AddDataValidation(SheetName, percentage); err != nil {
+		return fmt.Errorf("error adding percentage validation: %v", err)
+	}
+	return nil
+}
+
+// setColumnWidths sizes each column to its longest value, leaving room for
+// the filter button next to the header. Long text columns are capped and
+// LetterContent gets a fixed width.
+func setColumnWidths(f *excelize.File, employees []models.EmployeeData) {
+	for i, header := range Headers {
+		width := utf8.RuneCountInString(header) + 4
+		for _, emp := range employees {
+			value, _ := emp.Value(header)
+			length := len("2006-01-02")
+			if text, ok := value.(string); ok {
+				length = utf8.RuneCountInString(text)
+			}
+			width = max(width, length+2)
+		}
+		width = min(width, 40)
+		if header == "LetterContent" {
+			width = 80
+		}
+		col := getExcelColumn(i)
+		f.SetColWidth(SheetName, col, col, float64(width))
+	}
+}
+
+// headerIndex returns the column index of a header, or -1
+func headerIndex(header string) int {
+	for i, h := range Headers {
+		if h == header {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,68 @@
+package excel
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestDataValidationCoversAllRows(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Rows = 10
+	opts.Seed = 1
+	filename := filepath.Join(t.TempDir(), "validation.xlsx")
+	if err := Generate(filename, opts); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	validations, err := f.GetDataValidations(SheetName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The validation of each column's ranges, e.g. "E2:E100000"
+	byRange := make(map[string]*excelize.DataValidation)
+	for _, dv := range validations {
+		for _, ref := range strings.Fields(dv.Sqref) {
+			byRange[ref] = dv
+		}
+	}
+	rows := func(header string) string {
+		col := getExcelColumn(headerIndex(header))
+		return fmt.Sprintf("%s2:%s%d", col, col, maxRows+1)
+	}
+
+	for _, d := range dropdowns {
+		dv, ok := byRange[rows(d.Header)]
+		if !ok {
+			t.Errorf("%s: no validation on %s", d.Header, rows(d.Header))
+			continue
+		}
+		if dv.Type != "list" || !strings.Contains(dv.Formula1, d.Values[0]) {
+			t.Errorf("%s: validation %s %s, want a list of %v", d.Header, dv.Type, dv.Formula1, d.Values)
+		}
+	}
+	for _, header := range append([]string{"PercentageIncrease"}, amountHeaders...) {
+		dv, ok := byRange[rows(header)]
+		if !ok {
+			t.Errorf("%s: no validation on %s", header, rows(header))
+			continue
+		}
+		if dv.Type != "decimal" || dv.Operator != "between" || dv.ErrorStyle == nil || *dv.ErrorStyle != "stop" {
+			t.Errorf("%s: validation %s %s, want a decimal range that stops invalid values", header, dv.Type, dv.Operator)
+		}
+	}
+	if dv := byRange[rows("PercentageIncrease")]; dv != nil && (dv.Formula1 != "0" || dv.Formula2 != "100") {
+		t.Errorf("PercentageIncrease between %s and %s, want 0 and 100", dv.Formula1, dv.Formula2)
+	}
+	if dv := byRange[rows("BaseSalary")]; dv != nil && (dv.Formula1 != "0" || dv.Formula2 != fmt.Sprint(maxAmount)) {
+		t.Errorf("BaseSalary between %s and %s, want 0 and %d", dv.Formula1, dv.Formula2, maxAmount)
+	}
+}
@@ -0,0 +1,221 @@
+package excel
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Issue describes a problem found in a workbook row
+type Issue struct {
+	// Row is the 1-based worksheet row, or 0 for workbook-level issues
+	Row     int
+	Column  string
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Row == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("row %d, %s: %s", i.Row, i.Column, i.Message)
+}
+
+// Numeric columns that must parse as decimal numbers
+var numericHeaders = []string{
+	"HoursPerWeek", "Seniority", "SalaryStep",
+	"BaseSalary", "Supplements", "NewBaseSalary", "FullTimeBaseSalary", "NewFullTimeBaseSalary",
+	"GrossSalary", "NewGrossSalary",
+	"GeneralAdjustment", "IndividualAdjustment", "PercentageIncrease", "BackPay", "PensionIncrease",
+	"PensionRate", "EmployeePensionRate", "EmployerPensionRate",
+	"PensionContribution", "NewPensionContribution",
+}
+
+// Numeric columns without decimals
+var wholeNumberHeaders = map[string]bool{"HoursPerWeek": true, "Seniority": true, "SalaryStep": true}
+
+// isNumericHeader reports whether the column holds numbers
+func isNumericHeader(header string) bool {
+	for _, h := range numericHeaders {
+		if h == header {
+			return true
+		}
+	}
+	return false
+}
+
+var cprPattern = regexp.MustCompile(`^\d{6}-\d{4}$`)
+
+// Verify checks that a workbook has the expected headers and that every row
+// is consistent: valid and unique CPR and employee numbers, valid dates,
+// values from the dropdown lists, numeric salary columns, salary and pension
+// columns that reconcile, and manager references that form a tree within
+// each department.
+func Verify(filename string) ([]Issue, error) {
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Excel file: %v", err)
+	}
+	defer f.Close()
+
+	rows, err := readRows(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []Issue{{Message: fmt.Sprintf("sheet %s is empty", SheetName)}}, nil
+	}
+
+	var issues []Issue
+
+	// Headers
+	index := make(map[string]int)
+	for i, header := range rows[0] {
+		index[header] = i
+	}
+	for _, header := range Headers {
+		if _, ok := index[header]; !ok {
+			issues = append(issues, Issue{Message: fmt.Sprintf("missing column %s", header)})
+		}
+	}
+
+	var org []orgEntry
+	seenCPR := make(map[string]int)
+	seenEmployee := make(map[string]int)
+	for r, row := range rows[1:] {
+		rowNum := r + 2
+		if len(row) == 0 {
+			continue
+		}
+		cell := func(header string) string {
+			if i, ok := index[header]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+		add := func(column, format string, args ...interface{}) {
+			issues = append(issues, Issue{Row: rowNum, Column: column, Message: fmt.Sprintf(format, args...)})
+		}
+
+		cpr := cell("CPR")
+		if !cprPattern.MatchString(cpr) {
+			add("CPR", "invalid CPR number %q", cpr)
+		} else if first, ok := seenCPR[cpr]; ok {
+			add("CPR", "duplicate CPR number, also in row %d", first)
+		} else {
+			seenCPR[cpr] = rowNum
+		}
+
+		employeeNumber := cell("EmployeeNumber")
+		if employeeNumber == "" {
+			add("EmployeeNumber", "missing employee number")
+		} else if first, ok := seenEmployee[employeeNumber]; ok {
+			add("EmployeeNumber", "duplicate employee number, also in row %d", first)
+		} else {
+			seenEmployee[employeeNumber] = rowNum
+		}
+
+		if _, err := models.ParseDate(cell("EffectiveDate")); err != nil {
+			add("EffectiveDate", "%v", err)
+		}
+
+		if employmentType := cell("EmploymentType"); !isEmploymentType(employmentType) {
+			add("EmploymentType", "unknown employment type %q", employmentType)
+		}
+
+		if role := cell("Role"); !isRole(role) {
+			add("Role", "unknown role %q", role)
+		}
+		for _, d := range dropdowns {
+			if value := cell(d.Header); !d.allows(value) {
+				add(d.Header, "unknown %s %q", d.Header, value)
+			}
+		}
+		org = append(org, orgEntry{
+			Row:            rowNum,
+			EmployeeNumber: employeeNumber,
+			Name:           cell("FirstName") + " " + cell("LastName"),
+			Department:     cell("Department"),
+			Role:           cell("Role"),
+			Manager:        cell("ManagerEmployeeNumber"),
+			ManagerName:    cell("ManagerName"),
+		})
+
+		values := make(map[string]float64)
+		valid := true
+		for _, header := range numericHeaders {
+			v, err := strconv.ParseFloat(cell(header), 64)
+			if err != nil {
+				add(header, "not a number: %q", cell(header))
+				valid = false
+				continue
+			}
+			values[header] = v
+		}
+		if !valid {
+			continue
+		}
+
+		// Amounts are rounded to øre, so allow a small difference
+		if diff := values["BaseSalary"] + values["GeneralAdjustment"] + values["IndividualAdjustment"] - values["NewBaseSalary"]; math.Abs(diff) > 0.02 {
+			add("NewBaseSalary", "BaseSalary + GeneralAdjustment + IndividualAdjustment differs by %.2f kr.", diff)
+		}
+		if hours := values["HoursPerWeek"]; hours <= 0 || hours > 48 {
+			add("HoursPerWeek", "%g hours is not a working week", hours)
+		} else {
+			factor := models.FullTimeFactor(hours)
+			if diff := values["FullTimeBaseSalary"]*factor - values["BaseSalary"]; math.Abs(diff) > 0.02 {
+				add("BaseSalary", "FullTimeBaseSalary pro-rated to %g hours differs by %.2f kr.", hours, diff)
+			}
+			if diff := values["NewFullTimeBaseSalary"]*factor - values["NewBaseSalary"]; math.Abs(diff) > 0.02 {
+				add("NewBaseSalary", "NewFullTimeBaseSalary pro-rated to %g hours differs by %.2f kr.", hours, diff)
+			}
+		}
+		if values["SalaryStep"] < 1 || values["SalaryStep"] != math.Trunc(values["SalaryStep"]) {
+			add("SalaryStep", "not a step number: %s", cell("SalaryStep"))
+		}
+		if values["Seniority"] < 0 {
+			add("Seniority", "seniority is negative")
+		}
+		if values["Supplements"] < 0 || values["Supplements"] > values["BaseSalary"] {
+			add("Supplements", "supplements of %.2f kr. are not part of BaseSalary", values["Supplements"])
+		}
+		if values["BaseSalary"] > 0 {
+			pct := values["IndividualAdjustment"] / values["BaseSalary"] * 100
+			if math.Abs(pct-values["PercentageIncrease"]) > 0.01 {
+				add("PercentageIncrease", "adjustment is %.2f%% of BaseSalary, not %.2f%%", pct, values["PercentageIncrease"])
+			}
+		}
+		if values["NewGrossSalary"] < values["GrossSalary"] {
+			add("NewGrossSalary", "new gross salary is lower than the current")
+		}
+		if values["BackPay"] < 0 {
+			add("BackPay", "back pay is negative")
+		}
+
+		pension := models.Pension{
+			EmployeeRate:  values["EmployeePensionRate"],
+			EmployerRate:  values["EmployerPensionRate"],
+			Increase:      values["PensionIncrease"],
+			BaseSalary:    values["BaseSalary"],
+			NewBaseSalary: values["NewBaseSalary"],
+		}
+		if diff := pension.Rate() - values["PensionRate"]; math.Abs(diff) > 0.01 {
+			add("PensionRate", "employee and employer rates add up to %.2f%%, not %.2f%%", pension.Rate(), values["PensionRate"])
+		}
+		if diff := pension.Contribution() - values["PensionContribution"]; math.Abs(diff) > 0.02 {
+			add("PensionContribution", "PensionRate of BaseSalary differs by %.2f kr.", diff)
+		}
+		if diff := pension.NewContribution() - values["NewPensionContribution"]; math.Abs(diff) > 0.02 {
+			add("NewPensionContribution", "new pension rate of NewBaseSalary differs by %.2f kr.", diff)
+		}
+	}
+
+	issues = append(issues, verifyOrganisation(org)...)
+	return issues, nil
+}
@@ -0,0 +1,423 @@
+// Package filter evaluates row filter expressions over workbook header names,
+// e.g. `Department == "IT" && PercentageIncrease > 3`.
+//
+// Supported syntax: header names, string literals in double quotes, numbers,
+// comparisons (== != < <= > >=), the substring operator `contains`,
+// logical operators (&& || !) and parentheses. Comparisons are numeric when
+// both sides parse as numbers and textual otherwise.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Row gives access to the value of a column by header name
+type Row interface {
+	Field(name string) (string, bool)
+}
+
+// Expr is a parsed filter expression
+type Expr struct {
+	source string
+	root   node
+	fields []string
+}
+
+// Parse parses a filter expression
+func Parse(source string) (*Expr, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+
+	return &Expr{source: source, root: root, fields: p.fields}, nil
+}
+
+// String returns the source of the expression
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Fields returns the header names the expression refers to, once each, in
+// order of appearance. Match only looks up the fields it needs, so callers
+// check them against the known headers up front.
+func (e *Expr) Fields() []string {
+	return e.fields
+}
+
+// Match reports whether the row satisfies the expression.
+// It returns an error if the expression refers to an unknown header.
+func (e *Expr) Match(row Row) (bool, error) {
+	v, err := e.root.eval(row)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q does not evaluate to true or false", e.source)
+	}
+	return b, nil
+}
+
+// Token kinds
+const (
+	tokenEOF = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+// tokenize splits the source into tokens
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case r == '"':
+			start := i
+			var b strings.Builder
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string starting at position %d", start)
+			}
+			i++
+			tokens = append(tokens, token{tokenString, b.String(), start})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i++; i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.'); i++ {
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[start:i]), start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i++; i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_'); i++ {
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[start:i]), start})
+		default:
+			start := i
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					op = two
+				}
+			}
+			switch op {
+			case "==", "!=", "<=", ">=", "&&", "||", "<", ">", "!":
+			default:
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, start)
+			}
+			i += len([]rune(op))
+			tokens = append(tokens, token{tokenOp, op, start})
+		}
+	}
+	return append(tokens, token{tokenEOF, "end of expression", len(runes)}), nil
+}
+
+// parser is a recursive descent parser with the precedence
+// || < && < ! < comparison < operand
+type parser struct {
+	tokens []token
+	pos    int
+	fields []string
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// addField records a header name the expression refers to
+func (p *parser) addField(name string) {
+	for _, f := range p.fields {
+		if f == name {
+			return
+		}
+	}
+	p.fields = append(p.fields, name)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOp && p.peek().text == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOp && p.peek().text == "&&" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.peek().kind == tokenOp && p.peek().text == "!" {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	isComparison := t.kind == tokenOp && t.text != "&&" && t.text != "||" && t.text != "!"
+	if t.kind == tokenIdent && t.text == "contains" {
+		isComparison = true
+	}
+	if !isComparison {
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return &compareNode{op: t.text, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenIdent:
+		switch t.text {
+		case "true", "false":
+			return &literalNode{value: t.text == "true"}, nil
+		}
+		p.addField(t.text)
+		return &fieldNode{name: t.text}, nil
+	case tokenString:
+		return &literalNode{value: t.text}, nil
+	case tokenNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.text, t.pos)
+		}
+		return &literalNode{value: n}, nil
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ) at position %d, got %q", closing.pos, closing.text)
+		}
+		return inner, nil
+	default:
+		return nil, fmt.Errorf("expected header name or value at position %d, got %q", t.pos, t.text)
+	}
+}
+
+// node is an expression tree node. eval returns a string, float64 or bool.
+type node interface {
+	eval(row Row) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(Row) (interface{}, error) {
+	return n.value, nil
+}
+
+type fieldNode struct {
+	name string
+}
+
+func (n *fieldNode) eval(row Row) (interface{}, error) {
+	value, ok := row.Field(n.name)
+	if !ok {
+		return nil, fmt.Errorf("unknown header %q", n.name)
+	}
+	return value, nil
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(row Row) (interface{}, error) {
+	v, err := n.operand.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("! needs a true/false operand")
+	}
+	return !b, nil
+}
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func (n *logicalNode) eval(row Row) (interface{}, error) {
+	lv, err := n.left.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	l, ok := lv.(bool)
+	if !ok {
+		return nil, fmt.Errorf("%s needs true/false operands", n.op)
+	}
+
+	// Short-circuit evaluation
+	if (n.op == "&&" && !l) || (n.op == "||" && l) {
+		return l, nil
+	}
+
+	rv, err := n.right.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := rv.(bool)
+	if !ok {
+		return nil, fmt.Errorf("%s needs true/false operands", n.op)
+	}
+	return r, nil
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(row Row) (interface{}, error) {
+	lv, err := n.left.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	rv, err := n.right.eval(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "contains" {
+		return strings.Contains(strings.ToLower(toString(lv)), strings.ToLower(toString(rv))), nil
+	}
+
+	// Compare numerically when both sides are numbers
+	var cmp int
+	ln, lok := toNumber(lv)
+	rn, rok := toNumber(rv)
+	if lok && rok {
+		switch {
+		case ln < rn:
+			cmp = -1
+		case ln > rn:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(toString(lv), toString(rv))
+	}
+
+	switch n.op {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", n.op)
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,184 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// row is a Row backed by a map
+type row map[string]string
+
+func (r row) Field(name string) (string, bool) {
+	value, ok := r[name]
+	return value, ok
+}
+
+var employee = row{
+	"CPR":                "010190-1234",
+	"EmployeeNumber":     "10042",
+	"Department":         "Customer Service",
+	"PercentageIncrease": "3.50",
+	"Seniority":          "10",
+	"AdditionalNotes":    `Said "yes" to the offer`,
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		// Precedence: || < && < ! < comparison
+		{`Department == "IT" || Department == "Customer Service" && Seniority > 5`, true},
+		{`Department == "Customer Service" || Department == "IT" && Seniority > 50`, true},
+		{`(Department == "Customer Service" || Department == "IT") && Seniority > 50`, false},
+		{`!Department == "IT"`, true},
+		{`!Department == "IT" && Seniority < 5`, false},
+		{`!(Department == "IT" || Seniority == 10)`, false},
+		{`!!(Seniority == 10)`, true},
+		{`true && !false`, true},
+
+		// Numbers compare numerically, text textually
+		{`Seniority > 9`, true},
+		{`Seniority > "9"`, true},
+		{`PercentageIncrease == 3.5`, true},
+		{`PercentageIncrease >= 3.51`, false},
+		{`Seniority > -1`, true},
+		{`Department < "Finance"`, true},
+		{`Department > "customer"`, false},
+		{`Department == "customer service"`, false},
+		{`EmployeeNumber != "10042"`, false},
+
+		// contains ignores case
+		{`Department contains "service"`, true},
+		{`Department contains "SERVICE"`, true},
+		{`Department contains "IT"`, false},
+		{`CPR contains "-1234"`, true},
+
+		// Escaped quotes and backslashes in strings
+		{`AdditionalNotes == "Said \"yes\" to the offer"`, true},
+		{`AdditionalNotes contains "\"yes\""`, true},
+		{`AdditionalNotes contains "\y"`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			got, err := expr.Match(employee)
+			if err != nil {
+				t.Fatalf("Match: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`Department = "IT"`, `unexpected character '=' at position 11`},
+		{`Department == "IT`, `unterminated string starting at position 14`},
+		{`Seniority > 5 & Department == "IT"`, `unexpected character '&' at position 14`},
+		{`(Seniority > 5`, `expected ) at position 14, got "end of expression"`},
+		{`Seniority >`, `expected header name or value at position 11, got "end of expression"`},
+		{`Seniority > 5 Department`, `unexpected "Department" at position 14`},
+		{`&& Seniority > 5`, `expected header name or value at position 0, got "&&"`},
+		{`Seniority > 1.2.3`, `invalid number "1.2.3" at position 12`},
+		{``, `expected header name or value at position 0, got "end of expression"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if err == nil {
+				t.Fatalf("Parse succeeded, want error %q", tt.want)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("got error %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`Departmnet == "IT"`, `unknown header "Departmnet"`},
+		{`Department`, `does not evaluate to true or false`},
+		{`!Department`, `! needs a true/false operand`},
+		{`Seniority > 5 && Department`, `&& needs true/false operands`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			_, err = expr.Match(employee)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	expr, err := Parse(`Department == "X" && (Pecentage > 3 || !(Department contains "y")) && true`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []string{"Department", "Pecentage"}
+	if got := expr.Fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestLoadList(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "list.txt")
+	content := "# Employees to resend\n\n10042, 10043;10044\n  # indented comment\n020290-5678\t10045\n"
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	list, err := LoadList(filename)
+	if err != nil {
+		t.Fatalf("LoadList: %v", err)
+	}
+	want := List{"10042": true, "10043": true, "10044": true, "020290-5678": true, "10045": true}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("got %v, want %v", list, want)
+	}
+
+	tests := []struct {
+		name string
+		row  row
+		want bool
+	}{
+		{"employee number", row{"EmployeeNumber": "10043", "CPR": "010101-0000"}, true},
+		{"CPR", row{"EmployeeNumber": "99999", "CPR": "020290-5678"}, true},
+		{"neither", row{"EmployeeNumber": "99999", "CPR": "010101-0000"}, false},
+		{"comment is not an entry", row{"EmployeeNumber": "#", "CPR": "Employees"}, false},
+		{"no columns", row{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := list.Contains(tt.row); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadListMissingFile(t *testing.T) {
+	if _, err := LoadList(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadList succeeded for a missing file")
+	}
+}
@@ -0,0 +1,48 @@
+package filter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// List is a set of employee numbers or CPR numbers read from a list file
+type List map[string]bool
+
+// LoadList reads a list file with one employee number or CPR number per line.
+// Entries may also be separated by commas or spaces. Blank lines and lines
+// starting with # are ignored.
+func LoadList(filename string) (List, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open list file: %v", err)
+	}
+	defer file.Close()
+
+	list := make(List)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, entry := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' || r == ';' }) {
+			list[entry] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read list file %s: %v", filename, err)
+	}
+	return list, nil
+}
+
+// Contains reports whether the row's EmployeeNumber or CPR is in the list
+func (l List) Contains(row Row) bool {
+	for _, header := range []string{"EmployeeNumber", "CPR"} {
+		if value, ok := row.Field(header); ok && l[value] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Agreement is a collective agreement (overenskomst) and the general
+// regulation it brings in a campaign
+type Agreement struct {
+	// Code identifies the agreement in the workbook, e.g. "HK"
+	Code string
+	// Name is the full agreement name used in letters, e.g. "HK Privat"
+	Name string
+	// EffectiveDate is when the agreement's general regulation applies
+	EffectiveDate Date
+	// GeneralIncrease is the general pay increase in percent
+	GeneralIncrease float64
+	// PensionIncrease is the increase of the employer pension rate in percentage points
+	PensionIncrease float64
+	// EmployeePensionRate and EmployerPensionRate are the pension rates before
+	// the regulation, in percent of the base salary
+	EmployeePensionRate float64
+	EmployerPensionRate float64
+}
+
+// EmployeeGroup returns the employee group covered, e.g. "HK medarbejdere"
+func (a Agreement) EmployeeGroup() string {
+	return a.Code + " medarbejdere"
+}
+
+// Regulations returns the agreed changes as bullet texts for letters
+func (a Agreement) Regulations() []string {
+	var lines []string
+	if a.GeneralIncrease > 0 {
+		lines = append(lines, fmt.Sprintf("Generel lønstigning på %.2f%%", a.GeneralIncrease))
+	}
+	if a.PensionIncrease > 0 {
+		lines = append(lines, fmt.Sprintf("Forhøjelse af pensionsbidrag med %.2f%%", a.PensionIncrease))
+	}
+	return lines
+}
+
+// DefaultAgreements returns the agreements covered by the default campaign
+func DefaultAgreements(year int) []Agreement {
+	return []Agreement{
+		{Code: "HK", Name: "HK Privat", EffectiveDate: NewDate(year, time.May, 1),
+			GeneralIncrease: 2.00, PensionIncrease: 1.00, EmployeePensionRate: 4, EmployerPensionRate: 8},
+		{Code: "3F", Name: "Industriens Overenskomst (3F)", EffectiveDate: NewDate(year, time.March, 1),
+			GeneralIncrease: 2.50, PensionIncrease: 0.50, EmployeePensionRate: 4, EmployerPensionRate: 8},
+		{Code: "DJØF", Name: "DJØF Privat", EffectiveDate: NewDate(year, time.April, 1),
+			GeneralIncrease: 2.20, PensionIncrease: 1.00, EmployeePensionRate: 5, EmployerPensionRate: 10},
+		{Code: "Dansk Metal", Name: "Industriens Overenskomst (Dansk Metal)", EffectiveDate: NewDate(year, time.March, 1),
+			GeneralIncrease: 2.50, PensionIncrease: 0.50, EmployeePensionRate: 4, EmployerPensionRate: 8.5},
+	}
+}
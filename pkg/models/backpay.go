@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// RetroactiveMonths returns the number of months from the effective date up
+// to the payout month, which already pays the new salary. A month the
+// effective date falls within counts pro rata by calendar days. Back pay is
+// limited to the campaign year: an earlier effective date counts from 1. januar.
+func (c Campaign) RetroactiveMonths(effective Date) float64 {
+	payoutMonth := NewDate(c.Year, c.PayoutMonth, 1)
+	if effective.IsZero() || !effective.Before(payoutMonth) {
+		return 0
+	}
+	effective = c.retroactiveStart(effective)
+
+	months := 0.0
+	for month := effective.FirstOfMonth(); month.Before(payoutMonth); month = DateOf(month.AddDate(0, 1, 0)) {
+		daysInMonth := month.AddDate(0, 1, -1).Day()
+		from := month
+		if effective.After(from) {
+			from = effective
+		}
+		months += float64(daysInMonth-from.Day()+1) / float64(daysInMonth)
+	}
+	return months
+}
+
+// MaxRetroactiveMonths returns the longest back pay period of the campaign,
+// from 1. januar up to the payout month
+func (c Campaign) MaxRetroactiveMonths() int {
+	return int(c.PayoutMonth) - 1
+}
+
+// retroactiveStart returns the first day back pay is owed for: the effective
+// date, but no earlier than the start of the campaign year
+func (c Campaign) retroactiveStart(effective Date) Date {
+	if start := NewDate(c.Year, time.January, 1); effective.Before(start) {
+		return start
+	}
+	return effective
+}
+
+// RetroactivePeriod returns the first and last day covered by back pay.
+// It reports false if there is no back pay for the effective date.
+func (c Campaign) RetroactivePeriod(effective Date) (Date, Date, bool) {
+	if c.RetroactiveMonths(effective) == 0 {
+		return Date{}, Date{}, false
+	}
+	return c.retroactiveStart(effective), NewDate(c.Year, c.PayoutMonth, 1).AddDays(-1), true
+}
+
+// BackPay returns the retroactive amount owed for the months before the
+// payout month, rounded to øre: the monthly difference between the new and
+// current gross salary for each month from the effective date.
+func (c Campaign) BackPay(effective Date, grossSalary, newGrossSalary float64) float64 {
+	amount := (newGrossSalary - grossSalary) * c.RetroactiveMonths(effective)
+	return math.Round(amount*100) / 100
+}
+
+// BackPayText returns the back pay sentence used in letters, or an empty
+// string if there is no back pay
+func (c Campaign) BackPayText(effective Date, amount float64) string {
+	from, to, ok := c.RetroactivePeriod(effective)
+	if !ok || amount <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("Efterbetalingen for perioden %s til %s udgør %.2f kr. og udbetales sammen med lønnen %s.",
+		from.Danish(), to.Danish(), amount, c.PayoutText())
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetroactiveMonths(t *testing.T) {
+	campaign := DefaultCampaign() // payout in June 2025
+	tests := []struct {
+		name      string
+		effective Date
+		want      float64
+	}{
+		{"first of month", NewDate(2025, time.March, 1), 3},
+		{"mid-month", NewDate(2025, time.March, 15), 2 + 17.0/31},
+		{"last day before payout", NewDate(2025, time.May, 31), 1.0 / 31},
+		{"start of the year", NewDate(2025, time.January, 1), 5},
+		{"first of the payout month", NewDate(2025, time.June, 1), 0},
+		{"in the payout month", NewDate(2025, time.June, 15), 0},
+		{"after payout", NewDate(2025, time.August, 1), 0},
+		{"previous year", NewDate(2024, time.March, 1), 5},
+		{"no date", Date{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := campaign.RetroactiveMonths(tt.effective); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("RetroactiveMonths(%s) = %.4f, want %.4f", tt.effective, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackPay(t *testing.T) {
+	campaign := DefaultCampaign()
+	tests := []struct {
+		name      string
+		effective Date
+		want      float64
+	}{
+		{"whole months", NewDate(2025, time.March, 1), 3000},
+		{"mid-month rounded to øre", NewDate(2025, time.March, 15), 2548.39},
+		{"in the payout month", NewDate(2025, time.June, 2), 0},
+		{"after payout", NewDate(2025, time.September, 1), 0},
+		{"previous year capped", NewDate(2024, time.November, 1), 5000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := campaign.BackPay(tt.effective, 30000, 31000); got != tt.want {
+				t.Errorf("BackPay(%s) = %.2f, want %.2f", tt.effective, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackPayText(t *testing.T) {
+	campaign := DefaultCampaign()
+	tests := []struct {
+		name      string
+		effective Date
+		amount    float64
+		want      string
+	}{
+		{"mid-month", NewDate(2025, time.March, 15), 2548.39,
+			"Efterbetalingen for perioden 15. marts 2025 til 31. maj 2025 udgør 2548.39 kr. og udbetales sammen med lønnen den 30. juni 2025."},
+		{"previous year", NewDate(2024, time.November, 1), 5000,
+			"Efterbetalingen for perioden 1. januar 2025 til 31. maj 2025 udgør 5000.00 kr."},
+		{"in the payout month", NewDate(2025, time.June, 15), 100, ""},
+		{"after payout", NewDate(2025, time.July, 1), 100, ""},
+		{"no amount", NewDate(2025, time.March, 1), 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := campaign.BackPayText(tt.effective, tt.amount)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("got %q, want no text", got)
+				}
+				return
+			}
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dsb-excel-generator/pkg/calendar"
+)
+
+// DanishMonths holds the Danish month names, indexed by time.Month - 1
+var DanishMonths = []string{
+	"januar", "februar", "marts", "april", "maj", "juni",
+	"juli", "august", "september", "oktober", "november", "december",
+}
+
+// ParseDanishMonth parses a Danish month name such as "juni"
+func ParseDanishMonth(name string) (time.Month, error) {
+	for i, month := range DanishMonths {
+		if strings.EqualFold(strings.TrimSpace(name), month) {
+			return time.Month(i + 1), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown Danish month %q", name)
+}
+
+// Campaign describes a salary regulation campaign. Its values are
+// interpolated into letter titles, file names, metadata and body text.
+type Campaign struct {
+	// Title is the letter title, e.g. "Lønregulering 2025"
+	Title string
+	// Year of the regulation
+	Year int
+	// Agreement is the code of the default agreement, used for employees
+	// without one, e.g. "HK"
+	Agreement string
+	// Agreements are the collective agreements covered by the campaign
+	Agreements []Agreement
+	// PayoutMonth is the month of the salary payout that includes the regulation
+	PayoutMonth time.Month
+}
+
+// DefaultCampaign returns the Lønregulering 2025 campaign for HK employees
+func DefaultCampaign() Campaign {
+	return NewCampaign(2025)
+}
+
+// NewCampaign returns the default campaign for HK employees in a year,
+// with the title and agreement dates of that year
+func NewCampaign(year int) Campaign {
+	return Campaign{
+		Title:       CampaignTitle(year),
+		Year:        year,
+		Agreement:   "HK",
+		Agreements:  DefaultAgreements(year),
+		PayoutMonth: time.June,
+	}
+}
+
+// CampaignTitle returns the default letter title of a year, e.g. "Lønregulering 2025"
+func CampaignTitle(year int) string {
+	return fmt.Sprintf("Lønregulering %d", year)
+}
+
+// PayoutDate returns the payday of the payout month, its last banking day
+func (c Campaign) PayoutDate() Date {
+	return DateOf(calendar.LastBankingDay(c.Year, c.PayoutMonth))
+}
+
+// PayoutText returns the payout timing as used in letters, e.g. "den 30. juni 2025"
+func (c Campaign) PayoutText() string {
+	return "den " + c.PayoutDate().Danish()
+}
+
+// AgreementFor returns the agreement with the code, or the default agreement
+// if the campaign has no agreement with that code
+func (c Campaign) AgreementFor(code string) Agreement {
+	for _, a := range c.Agreements {
+		if a.Code == code {
+			return a
+		}
+	}
+	for _, a := range c.Agreements {
+		if a.Code == c.Agreement {
+			return a
+		}
+	}
+	return Agreement{Code: c.Agreement}
+}
+
+// RegulationName returns the definite form used in body text, e.g. "Lønreguleringen 2025"
+func (c Campaign) RegulationName() string {
+	return fmt.Sprintf("Lønreguleringen %d", c.Year)
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var cprPattern = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})-?(\d)(\d{3})$`)
+
+// Genders as registered in the CPR
+const (
+	GenderFemale = "Female"
+	GenderMale   = "Male"
+)
+
+// CPRBirthDate returns the birth date encoded in a CPR number (DDMMYY-XXXX).
+// The century follows from the year and the first digit of the sequence
+// number, as defined by the CPR office.
+func CPRBirthDate(cpr string) (Date, error) {
+	m := cprPattern.FindStringSubmatch(cpr)
+	if m == nil {
+		return Date{}, fmt.Errorf("%q is not a CPR number like 010190-1234", cpr)
+	}
+	day, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	yy, _ := strconv.Atoi(m[3])
+	digit, _ := strconv.Atoi(m[4])
+
+	century := 1900
+	switch {
+	case digit == 4 || digit == 9:
+		if yy <= 36 {
+			century = 2000
+		}
+	case digit >= 5 && digit <= 8:
+		if yy <= 57 {
+			century = 2000
+		} else {
+			century = 1800
+		}
+	}
+
+	d := NewDate(century+yy, time.Month(month), day)
+	if d.Day() != day || int(d.Month()) != month {
+		return Date{}, fmt.Errorf("%q does not contain a valid birth date", cpr)
+	}
+	return d, nil
+}
+
+// AgeOn returns the age in whole years on the given date of a person born on birth
+func AgeOn(birth, on Date) int {
+	age := on.Year() - birth.Year()
+	if on.Month() < birth.Month() || (on.Month() == birth.Month() && on.Day() < birth.Day()) {
+		age--
+	}
+	return age
+}
+
+// CPRGender returns the gender registered in a CPR number: the last digit
+// is odd for men and even for women
+func CPRGender(cpr string) (string, error) {
+	if !cprPattern.MatchString(cpr) {
+		return "", fmt.Errorf("%q is not a CPR number like 010190-1234", cpr)
+	}
+	if (cpr[len(cpr)-1]-'0')%2 == 1 {
+		return GenderMale, nil
+	}
+	return GenderFemale, nil
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCPRBirthDate(t *testing.T) {
+	tests := []struct {
+		cpr  string
+		want Date
+		ok   bool
+	}{
+		{"010190-1234", NewDate(1990, time.January, 1), true},
+		{"0101901234", NewDate(1990, time.January, 1), true},
+		{"311299-3999", NewDate(1999, time.December, 31), true},
+		// 4 and 9: 2000-2036, otherwise 1937-1999
+		{"150336-4001", NewDate(2036, time.March, 15), true},
+		{"150337-4001", NewDate(1937, time.March, 15), true},
+		{"150305-9001", NewDate(2005, time.March, 15), true},
+		{"150399-9001", NewDate(1999, time.March, 15), true},
+		// 5 to 8: 2000-2057, otherwise 1858-1899
+		{"150357-5001", NewDate(2057, time.March, 15), true},
+		{"150358-5001", NewDate(1858, time.March, 15), true},
+		{"150310-6001", NewDate(2010, time.March, 15), true},
+		{"150310-7001", NewDate(2010, time.March, 15), true},
+		{"150399-8001", NewDate(1899, time.March, 15), true},
+		{"290200-4000", NewDate(2000, time.February, 29), true},
+		{"290200-1000", Date{}, false},
+		{"310490-1234", Date{}, false},
+		{"011390-1234", Date{}, false},
+		{"010190-123", Date{}, false},
+		{"", Date{}, false},
+	}
+	for _, tt := range tests {
+		got, err := CPRBirthDate(tt.cpr)
+		if (err == nil) != tt.ok || !got.Equal(tt.want.Time) {
+			t.Errorf("CPRBirthDate(%q) = %s, %v, want %s, ok %v", tt.cpr, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCPRGender(t *testing.T) {
+	tests := []struct {
+		cpr  string
+		want string
+	}{
+		{"010190-1230", GenderFemale},
+		{"010190-1231", GenderMale},
+		{"010190-1234", GenderFemale},
+		{"0101901239", GenderMale},
+		{"010190-123", ""},
+	}
+	for _, tt := range tests {
+		got, err := CPRGender(tt.cpr)
+		if got != tt.want || (err == nil) != (tt.want != "") {
+			t.Errorf("CPRGender(%q) = %q, %v, want %q", tt.cpr, got, err, tt.want)
+		}
+	}
+}
+
+func TestAgeOn(t *testing.T) {
+	birth := NewDate(1990, time.June, 15)
+	tests := []struct {
+		on   Date
+		want int
+	}{
+		{NewDate(2025, time.June, 14), 34},
+		{NewDate(2025, time.June, 15), 35},
+		{NewDate(2025, time.December, 31), 35},
+	}
+	for _, tt := range tests {
+		if got := AgeOn(birth, tt.on); got != tt.want {
+			t.Errorf("AgeOn(%s, %s) = %d, want %d", birth, tt.on, got, tt.want)
+		}
+	}
+}
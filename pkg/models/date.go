@@ -0,0 +1,120 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ISODateFormat is the layout used to store dates in the workbook
+const ISODateFormat = "2006-01-02"
+
+// Date is a calendar date without time of day.
+// It is stored as an ISO date (2025-03-01) and shown in letters in the
+// long Danish form (1. marts 2025).
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the date for the given year, month and day
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf returns the date part of t
+func DateOf(t time.Time) Date {
+	return NewDate(t.Year(), t.Month(), t.Day())
+}
+
+var danishDatePattern = regexp.MustCompile(`^(\d{1,2})\.\s*(\p{L}+)\s+(\d{4})$`)
+
+// ParseDate parses an ISO date (2025-03-01) or a long Danish date (1. marts 2025)
+func ParseDate(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(ISODateFormat, s); err == nil {
+		return DateOf(t), nil
+	}
+	if danishDatePattern.MatchString(s) {
+		// Report an unknown month or a day the month does not have
+		return ParseDanishDate(s)
+	}
+	return Date{}, fmt.Errorf("%q is not a date like 2025-03-01 or 1. marts 2025", s)
+}
+
+// ParseDanishDate parses a long Danish date such as "1. marts 2025"
+func ParseDanishDate(s string) (Date, error) {
+	m := danishDatePattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Date{}, fmt.Errorf("%q is not a Danish date like 1. marts 2025", s)
+	}
+
+	month, err := ParseDanishMonth(m[2])
+	if err != nil {
+		return Date{}, err
+	}
+	day, _ := strconv.Atoi(m[1])
+	year, _ := strconv.Atoi(m[3])
+
+	d := NewDate(year, month, day)
+	if d.Day() != day {
+		return Date{}, fmt.Errorf("%q is not a valid date: %s has no day %d", s, DanishMonths[month-1], day)
+	}
+	return d, nil
+}
+
+// Danish formats the date in long Danish form, e.g. "1. marts 2025"
+func (d Date) Danish() string {
+	if d.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d. %s %d", d.Day(), DanishMonths[d.Month()-1], d.Year())
+}
+
+// String formats the date as an ISO date, e.g. "2025-03-01"
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(ISODateFormat)
+}
+
+// MarshalText stores the date as an ISO date
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText reads an ISO or long Danish date; empty text is the zero date
+func (d *Date) UnmarshalText(text []byte) error {
+	if strings.TrimSpace(string(text)) == "" {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Before reports whether d is before other
+func (d Date) Before(other Date) bool {
+	return d.Time.Before(other.Time)
+}
+
+// After reports whether d is after other
+func (d Date) After(other Date) bool {
+	return d.Time.After(other.Time)
+}
+
+// AddDays returns the date n days later
+func (d Date) AddDays(n int) Date {
+	return Date{d.AddDate(0, 0, n)}
+}
+
+// FirstOfMonth returns the first day of the date's month
+func (d Date) FirstOfMonth() Date {
+	return NewDate(d.Year(), d.Month(), 1)
+}
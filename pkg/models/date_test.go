@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Date
+		ok   bool
+	}{
+		{"2025-03-01", NewDate(2025, time.March, 1), true},
+		{"  2025-12-31\n", NewDate(2025, time.December, 31), true},
+		{"1. marts 2025", NewDate(2025, time.March, 1), true},
+		{"15. juni 2025", NewDate(2025, time.June, 15), true},
+		{" 31. december 2024 ", NewDate(2024, time.December, 31), true},
+		{"1.maj 2025", NewDate(2025, time.May, 1), true},
+		{"1. Februar 2025", NewDate(2025, time.February, 1), true},
+		{"29. februar 2024", NewDate(2024, time.February, 29), true},
+		{"29. februar 2025", Date{}, false},
+		{"31. april 2025", Date{}, false},
+		{"0. marts 2025", Date{}, false},
+		{"2025-04-31", Date{}, false},
+		{"1. march 2025", Date{}, false},
+		{"1 marts 2025", Date{}, false},
+		{"marts 2025", Date{}, false},
+		{"01-03-2025", Date{}, false},
+		{"", Date{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in)
+		if (err == nil) != tt.ok || !got.Equal(tt.want.Time) {
+			t.Errorf("ParseDate(%q) = %s, %v, want %s, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseDateInvalidDay(t *testing.T) {
+	_, err := ParseDate("31. april 2025")
+	if want := `"31. april 2025" is not a valid date: april has no day 31`; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+}
+
+func TestParseDanishDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Date
+		ok   bool
+	}{
+		{"1. januar 2025", NewDate(2025, time.January, 1), true},
+		{"\t30. september 2025 ", NewDate(2025, time.September, 30), true},
+		{"31. september 2025", Date{}, false},
+		{"2025-03-01", Date{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseDanishDate(tt.in)
+		if (err == nil) != tt.ok || !got.Equal(tt.want.Time) {
+			t.Errorf("ParseDanishDate(%q) = %s, %v, want %s, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDateRoundTrip(t *testing.T) {
+	for month := time.January; month <= time.December; month++ {
+		d := NewDate(2025, month, 1)
+		for _, text := range []string{d.String(), d.Danish()} {
+			got, err := ParseDate(text)
+			if err != nil || !got.Equal(d.Time) {
+				t.Errorf("ParseDate(%q) = %s, %v, want %s", text, got, err, d)
+			}
+		}
+	}
+}
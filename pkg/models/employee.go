@@ -1,16 +1,153 @@
 package models
 
-// EmployeeData represents the data for a single employee
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Delivery methods for letters
+const (
+	DeliveryDigital  = "Digital Post"
+	DeliveryPhysical = "Physical Mail"
+)
+
+// Approval statuses of a row, filled in by HR in the workbook
+const (
+	ApprovalPending  = "Pending"
+	ApprovalApproved = "Approved"
+	ApprovalRejected = "Rejected"
+)
+
+// ApprovalStatuses lists the approval statuses
+var ApprovalStatuses = []string{ApprovalPending, ApprovalApproved, ApprovalRejected}
+
+// EmployeeData represents the data for a single employee.
+// Field names match the column headers in the generated workbook.
 type EmployeeData struct {
-	CPR                  string
-	FirstName            string
-	LastName             string
-	BaseSalary           string
-	NewBaseSalary        string
-	GrossSalary          string
-	NewGrossSalary       string
-	IndividualAdjustment string
-	PercentageIncrease   string
-	EffectiveDate        string
-	PensionIncrease      string
+	CPR                    string
+	FirstName              string
+	LastName               string
+	EmployeeNumber         string
+	Department             string
+	Role                   string
+	Agreement              string
+	EmploymentType         string
+	HoursPerWeek           string
+	Seniority              string
+	SalaryStep             string
+	Street                 string
+	HouseNumber            string
+	PostCode               string
+	City                   string
+	BaseSalary             string
+	Supplements            string
+	NewBaseSalary          string
+	FullTimeBaseSalary     string
+	NewFullTimeBaseSalary  string
+	GrossSalary            string
+	NewGrossSalary         string
+	GeneralAdjustment      string
+	IndividualAdjustment   string
+	PercentageIncrease     string
+	EffectiveDate          Date
+	BackPay                string
+	PensionIncrease        string
+	PensionRate            string
+	EmployeePensionRate    string
+	EmployerPensionRate    string
+	PensionContribution    string
+	NewPensionContribution string
+	LetterType             string
+	ChangeDescription      string
+	ManagerEmployeeNumber  string
+	ManagerName            string
+	AdditionalNotes        string
+	ApprovalStatus         string
+	ApprovedBy             string
+	DeliveryMethod         string
+	DocumentType           string
+	CaseNumber             string
+	SecurityLevel          string
+	LetterContent          string
+
+	// Row is the workbook row the employee was read from, or 0 if the
+	// employee was not read from a workbook. It is not a column.
+	Row int
+}
+
+// FullName returns the first and last name separated by a space
+func (e EmployeeData) FullName() string {
+	return e.FirstName + " " + e.LastName
+}
+
+// HasAddress reports whether the employee has a complete postal address
+func (e EmployeeData) HasAddress() bool {
+	return e.Street != "" && e.PostCode != "" && e.City != ""
+}
+
+// Field returns the value of the field matching a workbook header as text.
+// Dates are returned as ISO dates.
+func (e EmployeeData) Field(name string) (string, bool) {
+	v := reflect.ValueOf(e).FieldByName(name)
+	if !v.IsValid() {
+		return "", false
+	}
+	if v.Kind() == reflect.String {
+		return v.String(), true
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err == nil
+	}
+	return "", false
+}
+
+// Value returns the field matching a workbook header with its own type
+func (e EmployeeData) Value(name string) (interface{}, bool) {
+	v := reflect.ValueOf(e).FieldByName(name)
+	if !v.IsValid() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// SetField sets the field matching a workbook header from text.
+// It returns false if the header does not correspond to a field
+// or the text is not valid for the field's type.
+func (e *EmployeeData) SetField(name, value string) bool {
+	v := reflect.ValueOf(e).Elem().FieldByName(name)
+	if !v.IsValid() {
+		return false
+	}
+	if v.Kind() == reflect.String {
+		v.SetString(value)
+		return true
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value)) == nil
+	}
+	return false
+}
+
+// PlacementText returns the sentence placing the employee on the pay scale,
+// or an empty string if the step is unknown
+func PlacementText(step, seniority int, supplements float64) string {
+	if step <= 0 {
+		return ""
+	}
+	text := fmt.Sprintf("Du er indplaceret på løntrin %d ud fra din anciennitet på %d år", step, seniority)
+	if supplements > 0 {
+		return text + fmt.Sprintf(", og dine tillæg udgør %.2f kr. om måneden.", supplements)
+	}
+	return text + "."
+}
+
+// Placement returns the pay scale placement sentence from the employee's columns
+func (e EmployeeData) Placement() string {
+	step, _ := strconv.Atoi(e.SalaryStep)
+	seniority, _ := strconv.Atoi(e.Seniority)
+	supplements, _ := strconv.ParseFloat(e.Supplements, 64)
+	return PlacementText(step, seniority, supplements)
 }
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Employment types
+const (
+	EmploymentMonthly = "Monthly"
+	EmploymentHourly  = "Hourly"
+	EmploymentTrainee = "Trainee"
+)
+
+// EmploymentTypes lists the known employment types
+var EmploymentTypes = []string{EmploymentMonthly, EmploymentHourly, EmploymentTrainee}
+
+// FullTimeHours is the weekly working time of a full-time employee
+const FullTimeHours = 37.0
+
+// HoursPerMonth returns the monthly working hours for the weekly hours,
+// e.g. 160.33 hours for full time
+func HoursPerMonth(hoursPerWeek float64) float64 {
+	return hoursPerWeek * 52 / 12
+}
+
+// FullTimeFactor returns the share of full time worked, e.g. 0.5 for 18.5 hours
+func FullTimeFactor(hoursPerWeek float64) float64 {
+	return hoursPerWeek / FullTimeHours
+}
+
+// Hours returns the weekly working hours, or full time if the column is missing
+func (e EmployeeData) Hours() float64 {
+	hours, err := strconv.ParseFloat(e.HoursPerWeek, 64)
+	if err != nil || hours <= 0 {
+		return FullTimeHours
+	}
+	return hours
+}
+
+// HoursText returns the sentence relating the letter's amounts to full time,
+// or an empty string for full-time monthly employees
+func HoursText(employmentType string, hoursPerWeek, newFullTimeBaseSalary float64) string {
+	if employmentType == EmploymentHourly {
+		return fmt.Sprintf("Du er timelønnet med %g timer om ugen. Din nye timeløn er %.2f kr., og omregnet til fuld tid (%g timer) udgør din nye basisløn %.2f kr. om måneden.",
+			hoursPerWeek, newFullTimeBaseSalary/HoursPerMonth(FullTimeHours), FullTimeHours, newFullTimeBaseSalary)
+	}
+	if hoursPerWeek < FullTimeHours {
+		return fmt.Sprintf("Du er ansat på deltid med %g timer om ugen, og beløbene i dette brev er forholdsmæssige. Omregnet til fuld tid (%g timer) udgør din nye basisløn %.2f kr.",
+			hoursPerWeek, FullTimeHours, newFullTimeBaseSalary)
+	}
+	return ""
+}
+
+// HoursText returns the full-time sentence from the employee's columns
+func (e EmployeeData) HoursText() string {
+	fullTime, err := strconv.ParseFloat(e.NewFullTimeBaseSalary, 64)
+	if err != nil {
+		return ""
+	}
+	return HoursText(e.EmploymentType, e.Hours(), fullTime)
+}
@@ -0,0 +1,67 @@
+package models
+
+// Roles in a department's organisation
+const (
+	RoleDepartmentHead = "Department Head"
+	RoleTeamLead       = "Team Lead"
+	RoleStaff          = "Staff"
+)
+
+// Roles lists the roles from the top of a department to the bottom
+var Roles = []string{RoleDepartmentHead, RoleTeamLead, RoleStaff}
+
+// IsManager reports whether the employee has people reporting to them
+func (e EmployeeData) IsManager() bool {
+	return e.Role == RoleDepartmentHead || e.Role == RoleTeamLead
+}
+
+// OrgTree links employees to the employees reporting to them through
+// ManagerEmployeeNumber
+type OrgTree struct {
+	heads   []EmployeeData
+	reports map[string][]EmployeeData
+}
+
+// NewOrgTree builds the organisation of the employees. Employees without a
+// manager are the tops of the tree, in the order given.
+func NewOrgTree(employees []EmployeeData) OrgTree {
+	tree := OrgTree{reports: make(map[string][]EmployeeData)}
+	for _, emp := range employees {
+		if emp.ManagerEmployeeNumber == "" {
+			tree.heads = append(tree.heads, emp)
+			continue
+		}
+		tree.reports[emp.ManagerEmployeeNumber] = append(tree.reports[emp.ManagerEmployeeNumber], emp)
+	}
+	return tree
+}
+
+// Heads returns the employees without a manager
+func (t OrgTree) Heads() []EmployeeData {
+	return t.heads
+}
+
+// Reports returns the employees reporting directly to an employee
+func (t OrgTree) Reports(employeeNumber string) []EmployeeData {
+	return t.reports[employeeNumber]
+}
+
+// Team returns everyone below an employee in the tree, each report followed
+// by their own team
+func (t OrgTree) Team(employeeNumber string) []EmployeeData {
+	var team []EmployeeData
+	seen := map[string]bool{employeeNumber: true}
+	var walk func(string)
+	walk = func(number string) {
+		for _, emp := range t.reports[number] {
+			if seen[emp.EmployeeNumber] {
+				continue // Guards against cycles in hand-edited workbooks
+			}
+			seen[emp.EmployeeNumber] = true
+			team = append(team, emp)
+			walk(emp.EmployeeNumber)
+		}
+	}
+	walk(employeeNumber)
+	return team
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"math"
+	"strconv"
+)
+
+// Pension holds an employee's pension rates and the base salaries they
+// apply to. Rates are percentages of the monthly base salary. The agreed
+// increase is paid by the employer.
+type Pension struct {
+	EmployeeRate  float64
+	EmployerRate  float64
+	Increase      float64
+	BaseSalary    float64
+	NewBaseSalary float64
+}
+
+// PensionShare is one line of a pension overview: a party's rate and
+// monthly contribution before and after the regulation
+type PensionShare struct {
+	Name            string
+	Rate            float64
+	NewRate         float64
+	Contribution    float64
+	NewContribution float64
+}
+
+// Rate returns the current total pension rate
+func (p Pension) Rate() float64 {
+	return p.EmployeeRate + p.EmployerRate
+}
+
+// NewRate returns the total pension rate after the increase
+func (p Pension) NewRate() float64 {
+	return p.Rate() + p.Increase
+}
+
+// Contribution returns the current monthly contribution in kroner
+func (p Pension) Contribution() float64 {
+	return monthlyContribution(p.BaseSalary, p.Rate())
+}
+
+// NewContribution returns the monthly contribution after the regulation
+func (p Pension) NewContribution() float64 {
+	return monthlyContribution(p.NewBaseSalary, p.NewRate())
+}
+
+// Shares returns the employer, employee and total lines of the overview
+func (p Pension) Shares() []PensionShare {
+	share := func(name string, rate, newRate float64) PensionShare {
+		return PensionShare{
+			Name:            name,
+			Rate:            rate,
+			NewRate:         newRate,
+			Contribution:    monthlyContribution(p.BaseSalary, rate),
+			NewContribution: monthlyContribution(p.NewBaseSalary, newRate),
+		}
+	}
+	return []PensionShare{
+		share("Arbejdsgiver", p.EmployerRate, p.EmployerRate+p.Increase),
+		share("Medarbejder", p.EmployeeRate, p.EmployeeRate),
+		share("I alt", p.Rate(), p.NewRate()),
+	}
+}
+
+// monthlyContribution returns rate percent of salary, rounded to øre
+func monthlyContribution(salary, rate float64) float64 {
+	return math.Round(salary*rate) / 100
+}
+
+// Pension returns the pension rates and salaries from the employee's columns.
+// Columns that are missing or not numbers count as zero.
+func (e EmployeeData) Pension() Pension {
+	number := func(s string) float64 {
+		v, _ := strconv.ParseFloat(s, 64)
+		return v
+	}
+	return Pension{
+		EmployeeRate:  number(e.EmployeePensionRate),
+		EmployerRate:  number(e.EmployerPensionRate),
+		Increase:      number(e.PensionIncrease),
+		BaseSalary:    number(e.BaseSalary),
+		NewBaseSalary: number(e.NewBaseSalary),
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPensionShares(t *testing.T) {
+	p := Pension{EmployeeRate: 4, EmployerRate: 8, Increase: 1, BaseSalary: 30000, NewBaseSalary: 30750}
+	want := []PensionShare{
+		// The employer pays the increase
+		{"Arbejdsgiver", 8, 9, 2400, 2767.50},
+		{"Medarbejder", 4, 4, 1200, 1230},
+		{"I alt", 12, 13, 3600, 3997.50},
+	}
+	got := p.Shares()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Shares() = %v\nwant %v", got, want)
+	}
+	if p.Contribution() != 3600 || p.NewContribution() != 3997.50 {
+		t.Errorf("contributions %.2f and %.2f, want 3600.00 and 3997.50", p.Contribution(), p.NewContribution())
+	}
+}
+
+func TestPensionRounding(t *testing.T) {
+	p := Pension{EmployeeRate: 4, EmployerRate: 8.5, BaseSalary: 31234.57, NewBaseSalary: 31234.57}
+	shares := p.Shares()
+	// 8.5% of 31234.57 is 2654.93845 and 4% is 1249.3828, rounded to øre
+	if shares[0].Contribution != 2654.94 || shares[1].Contribution != 1249.38 || shares[2].Contribution != 3904.32 {
+		t.Errorf("contributions %.2f, %.2f and %.2f, want 2654.94, 1249.38 and 3904.32",
+			shares[0].Contribution, shares[1].Contribution, shares[2].Contribution)
+	}
+	if shares[0].NewRate != shares[0].Rate {
+		t.Errorf("employer rate %.2f -> %.2f without an increase", shares[0].Rate, shares[0].NewRate)
+	}
+}
+
+func TestEmployeePension(t *testing.T) {
+	emp := EmployeeData{EmployeePensionRate: "4.00", EmployerPensionRate: "8.00", PensionIncrease: "n/a",
+		BaseSalary: "30000.00", NewBaseSalary: "30750.00"}
+	want := Pension{EmployeeRate: 4, EmployerRate: 8, BaseSalary: 30000, NewBaseSalary: 30750}
+	if got := emp.Pension(); got != want {
+		t.Errorf("Pension() = %+v, want %+v", got, want)
+	}
+}
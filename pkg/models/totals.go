@@ -0,0 +1,58 @@
+package models
+
+import "strconv"
+
+// Amount returns a numeric column as a number, or 0 if it is empty or not a number
+func (e EmployeeData) Amount(name string) float64 {
+	text, _ := e.Field(name)
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// TotalIncrease returns the increase from BaseSalary to NewBaseSalary in
+// percent, including the general increase
+func (e EmployeeData) TotalIncrease() float64 {
+	return Increase(e.Amount("BaseSalary"), e.Amount("NewBaseSalary"))
+}
+
+// Increase returns the increase from before to after in percent, or 0 if
+// before is 0
+func Increase(before, after float64) float64 {
+	if before == 0 {
+		return 0
+	}
+	return (after - before) / before * 100
+}
+
+// SalaryTotals sums the monthly salary columns of a group of employees
+type SalaryTotals struct {
+	Employees            int
+	BaseSalary           float64
+	NewBaseSalary        float64
+	IndividualAdjustment float64
+}
+
+// Totals sums the salary columns of the employees
+func Totals(employees []EmployeeData) SalaryTotals {
+	t := SalaryTotals{Employees: len(employees)}
+	for _, emp := range employees {
+		t.BaseSalary += emp.Amount("BaseSalary")
+		t.NewBaseSalary += emp.Amount("NewBaseSalary")
+		t.IndividualAdjustment += emp.Amount("IndividualAdjustment")
+	}
+	return t
+}
+
+// IndividualPercentage returns the individual adjustments in percent of the
+// base salaries
+func (t SalaryTotals) IndividualPercentage() float64 {
+	return Increase(t.BaseSalary, t.BaseSalary+t.IndividualAdjustment)
+}
+
+// TotalIncrease returns the increase of the base salaries in percent
+func (t SalaryTotals) TotalIncrease() float64 {
+	return Increase(t.BaseSalary, t.NewBaseSalary)
+}
@@ -0,0 +1,314 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dsb-excel-generator/pkg/excel"
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// BatchOptions controls the print-house batch output
+type BatchOptions struct {
+	// LettersPerFile splits the batch into several PDFs; 0 puts all letters in one file
+	LettersPerFile int
+	// Duplex inserts blank pages so every letter starts on the front of a new sheet
+	Duplex bool
+	// OMR configures the insertion marks read by the enveloping machine
+	OMR OMROptions
+}
+
+// OMROptions configures optical mark recognition (OMR) marks.
+// Marks are horizontal bars printed in the left margin of the front side
+// of every sheet. All positions are in mm.
+type OMROptions struct {
+	Enabled bool
+	// Left is the distance from the left paper edge to the start of each mark
+	Left float64
+	// Top is the vertical position of the first mark
+	Top float64
+	// Pitch is the vertical distance between mark positions
+	Pitch float64
+	// Length and Thickness set the size of each mark
+	Length    float64
+	Thickness float64
+	// SequenceBits adds a wrap-around sheet sequence (0-3 bits) so the
+	// machine can detect missing or swapped sheets
+	SequenceBits int
+	// Parity adds a mark that makes the number of marks on each sheet even
+	Parity bool
+}
+
+// DefaultOMROptions returns the common 1/6 inch mark layout
+func DefaultOMROptions() OMROptions {
+	return OMROptions{
+		Enabled:      true,
+		Left:         4,
+		Top:          120,
+		Pitch:        4.23,
+		Length:       8,
+		Thickness:    0.5,
+		SequenceBits: 3,
+		Parity:       true,
+	}
+}
+
+// BatchFile summarizes one PDF file of a print batch
+type BatchFile struct {
+	Name          string
+	Letters       int
+	Pages         int
+	BlankPages    int
+	Sheets        int
+	FirstPostCode string
+	LastPostCode  string
+}
+
+// JobTicket summarizes a print batch for the print provider
+type JobTicket struct {
+	Campaign  string
+	Created   time.Time
+	Files     []BatchFile
+	Letters   int
+	Pages     int
+	Sheets    int
+	Envelopes int
+	Duplex    bool
+	OMR       bool
+}
+
+// GeneratePrintBatch merges the letters for employees with postal delivery
+// into one or more print-ready PDFs sorted by postcode, and writes a job ticket
+// summary next to them
+func GeneratePrintBatch(excelFile string, outputDir string, opts Options, batch BatchOptions) (*JobTicket, error) {
+	opts = opts.withDefaults()
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	employees, err := excel.ReadEmployees(excelFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPreflight(employees, opts); err != nil {
+		return nil, err
+	}
+
+	selected, err := selectEmployees(employees, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only letters that go by physical mail and can be addressed
+	var letters []models.EmployeeData
+	for _, emp := range selected {
+		if emp.DeliveryMethod == models.DeliveryPhysical && emp.HasAddress() {
+			letters = append(letters, emp)
+		}
+	}
+	sortByPostCode(letters)
+	if opts.Limit > 0 && len(letters) > opts.Limit {
+		letters = letters[:opts.Limit]
+	}
+	if len(letters) == 0 {
+		return nil, fmt.Errorf("no letters for physical mail in %s", excelFile)
+	}
+
+	perFile := batch.LettersPerFile
+	if perFile <= 0 {
+		perFile = len(letters)
+	}
+
+	ticket := &JobTicket{
+		Campaign: opts.Campaign.Title,
+		Created:  time.Now(),
+		Duplex:   batch.Duplex,
+		OMR:      batch.OMR.Enabled,
+	}
+	for start := 0; start < len(letters); start += perFile {
+		end := start + perFile
+		if end > len(letters) {
+			end = len(letters)
+		}
+
+		name := fmt.Sprintf("printbatch-%03d.pdf", len(ticket.Files)+1)
+		file, err := writeBatchFile(letters[start:end], filepath.Join(outputDir, name), opts, batch)
+		if err != nil {
+			return nil, err
+		}
+		file.Name = name
+
+		ticket.Files = append(ticket.Files, *file)
+		ticket.Letters += file.Letters
+		ticket.Pages += file.Pages
+		ticket.Sheets += file.Sheets
+	}
+	ticket.Envelopes = ticket.Letters
+
+	ticketPath := filepath.Join(outputDir, "printbatch-job-ticket.txt")
+	if err := os.WriteFile(ticketPath, []byte(ticket.String()), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write job ticket: %v", err)
+	}
+
+	fmt.Printf("\nSuccessfully generated %d print file(s) with %d letters in %s\n",
+		len(ticket.Files), ticket.Letters, outputDir)
+	return ticket, nil
+}
+
+// sortByPostCode orders letters by postcode, then by street address and name
+func sortByPostCode(letters []models.EmployeeData) {
+	sort.SliceStable(letters, func(i, j int) bool {
+		a, b := letters[i], letters[j]
+		if a.PostCode != b.PostCode {
+			return a.PostCode < b.PostCode
+		}
+		if a.Street != b.Street {
+			return a.Street < b.Street
+		}
+		if c := compareHouseNumbers(a.HouseNumber, b.HouseNumber); c != 0 {
+			return c < 0
+		}
+		return a.FullName() < b.FullName()
+	})
+}
+
+// compareHouseNumbers orders house numbers by their number, then by the
+// letter or floor that follows it, so 2 < 10 < 12 < 12A < 12B. House
+// numbers without a number come last.
+func compareHouseNumbers(a, b string) int {
+	numberA, restA := splitHouseNumber(a)
+	numberB, restB := splitHouseNumber(b)
+	if numberA != numberB {
+		switch {
+		case numberA < 0:
+			return 1
+		case numberB < 0:
+			return -1
+		}
+		return numberA - numberB
+	}
+	return strings.Compare(strings.ToUpper(restA), strings.ToUpper(restB))
+}
+
+// splitHouseNumber returns the number a house number starts with, or -1 if
+// it does not start with one, and the rest, e.g. 12 and "A" for "12A"
+func splitHouseNumber(houseNumber string) (int, string) {
+	houseNumber = strings.TrimSpace(houseNumber)
+	digits := 0
+	for digits < len(houseNumber) && houseNumber[digits] >= '0' && houseNumber[digits] <= '9' {
+		digits++
+	}
+	number, err := strconv.Atoi(houseNumber[:digits])
+	if err != nil {
+		return -1, houseNumber
+	}
+	return number, strings.TrimSpace(houseNumber[digits:])
+}
+
+// writeBatchFile renders the letters into a single print file
+func writeBatchFile(letters []models.EmployeeData, outputPath string, opts Options, batch BatchOptions) (*BatchFile, error) {
+	pdf, tr := newLetterDocument(opts.Campaign, "Printbatch – "+opts.Campaign.Title)
+
+	file := &BatchFile{
+		Letters:       len(letters),
+		FirstPostCode: letters[0].PostCode,
+		LastPostCode:  letters[len(letters)-1].PostCode,
+	}
+
+	sheetSequence := 0
+	for _, emp := range letters {
+		pdf.AddPage()
+		firstPage := pdf.PageNo()
+		writeLetter(pdf, tr, emp, opts)
+		lastPage := pdf.PageNo()
+
+		// Pad to an even page count so the next letter starts on a new sheet
+		if batch.Duplex && (lastPage-firstPage+1)%2 == 1 {
+			pdf.AddPage()
+			lastPage = pdf.PageNo()
+			file.BlankPages++
+		}
+
+		pagesPerSheet := 1
+		if batch.Duplex {
+			pagesPerSheet = 2
+		}
+		sheets := (lastPage - firstPage + 1) / pagesPerSheet
+		file.Sheets += sheets
+
+		if batch.OMR.Enabled {
+			for sheet := 0; sheet < sheets; sheet++ {
+				sheetSequence++
+				pdf.SetPage(firstPage + sheet*pagesPerSheet)
+				drawOMRMarks(pdf, batch.OMR, sheetSequence, sheet == sheets-1)
+			}
+			pdf.SetPage(lastPage)
+		}
+	}
+	file.Pages = pdf.PageNo()
+
+	if err := pdf.OutputFileAndClose(outputPath); err != nil {
+		return nil, fmt.Errorf("failed to write print file: %v", err)
+	}
+	return file, nil
+}
+
+// drawOMRMarks prints the marks for one sheet: a start mark, an end-of-set mark
+// on the last sheet of a letter, the sequence bits and an optional parity mark
+func drawOMRMarks(pdf *fpdf.Fpdf, omr OMROptions, sequence int, lastSheet bool) {
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetLineWidth(omr.Thickness)
+	for i, on := range omrMarks(omr, sequence, lastSheet) {
+		if on {
+			y := omr.Top + float64(i)*omr.Pitch
+			pdf.Line(omr.Left, y, omr.Left+omr.Length, y)
+		}
+	}
+	pdf.SetLineWidth(0.2)
+}
+
+// omrMarks returns which mark positions of a sheet are printed, from the top
+func omrMarks(omr OMROptions, sequence int, lastSheet bool) []bool {
+	marks := []bool{true, lastSheet} // Start mark, insert (end of set) mark
+	for bit := 0; bit < omr.SequenceBits; bit++ {
+		marks = append(marks, sequence&(1<<bit) != 0)
+	}
+	if omr.Parity {
+		count := 0
+		for _, on := range marks {
+			if on {
+				count++
+			}
+		}
+		marks = append(marks, count%2 == 1)
+	}
+	return marks
+}
+
+// String formats the job ticket as plain text for the print provider
+func (t *JobTicket) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "PRINT JOB TICKET – %s\n", t.Campaign)
+	fmt.Fprintf(&b, "Created:    %s\n", t.Created.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "Duplex:     %t\n", t.Duplex)
+	fmt.Fprintf(&b, "OMR marks:  %t\n", t.OMR)
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "%-20s %8s %8s %8s %8s  %s\n", "File", "Letters", "Pages", "Blank", "Sheets", "Postcodes")
+	for _, f := range t.Files {
+		fmt.Fprintf(&b, "%-20s %8d %8d %8d %8d  %s-%s\n",
+			f.Name, f.Letters, f.Pages, f.BlankPages, f.Sheets, f.FirstPostCode, f.LastPostCode)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Total pages:     %d\n", t.Pages)
+	fmt.Fprintf(&b, "Total sheets:    %d\n", t.Sheets)
+	fmt.Fprintf(&b, "Total envelopes: %d\n", t.Envelopes)
+	return b.String()
+}
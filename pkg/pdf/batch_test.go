@@ -0,0 +1,143 @@
+package pdf
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"dsb-excel-generator/pkg/excel"
+	"dsb-excel-generator/pkg/models"
+)
+
+func TestSortByPostCode(t *testing.T) {
+	var letters []models.EmployeeData
+	for _, address := range []struct{ postCode, street, houseNumber string }{
+		{"8000", "Vestergade", "1"},
+		{"1620", "Vesterbrogade", "12B"},
+		{"1620", "Vesterbrogade", "2"},
+		{"1620", "Vesterbrogade", ""},
+		{"1620", "Vesterbrogade", "12a"},
+		{"1620", "Vesterbrogade", "12"},
+		{"1620", "Vesterbrogade", "10"},
+		{"1620", "Istedgade", "100"},
+	} {
+		letters = append(letters, models.EmployeeData{PostCode: address.postCode, Street: address.street, HouseNumber: address.houseNumber})
+	}
+	sortByPostCode(letters)
+
+	var got []string
+	for _, emp := range letters {
+		got = append(got, fmt.Sprintf("%s %s %s", emp.PostCode, emp.Street, emp.HouseNumber))
+	}
+	want := "[1620 Istedgade 100 1620 Vesterbrogade 2 1620 Vesterbrogade 10 1620 Vesterbrogade 12 " +
+		"1620 Vesterbrogade 12a 1620 Vesterbrogade 12B 1620 Vesterbrogade  8000 Vestergade 1]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("got %v\nwant %s", got, want)
+	}
+}
+
+func TestCompareHouseNumbers(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2", "10", -1},
+		{"12", "12A", -1},
+		{"12A", "12b", -1},
+		{"12 B", "12B", 0},
+		{"12, 2. tv.", "12, 3. th.", -1},
+		{"9Z", "10", -1},
+		{"", "1", 1},
+	}
+	for _, tt := range tests {
+		got := compareHouseNumbers(tt.a, tt.b)
+		if (got < 0) != (tt.want < 0) || (got > 0) != (tt.want > 0) {
+			t.Errorf("compareHouseNumbers(%q, %q) = %d, want sign of %d", tt.a, tt.b, got, tt.want)
+		}
+		if back := compareHouseNumbers(tt.b, tt.a); (back < 0) != (tt.want > 0) {
+			t.Errorf("compareHouseNumbers(%q, %q) = %d, not the reverse of %d", tt.b, tt.a, back, got)
+		}
+	}
+}
+
+func TestOMRMarks(t *testing.T) {
+	tests := []struct {
+		name      string
+		omr       OMROptions
+		sequence  int
+		lastSheet bool
+		want      string
+	}{
+		// Start, insert, three sequence bits from the lowest, parity
+		{"first sheet", DefaultOMROptions(), 1, false, "[true false true false false false]"},
+		{"last sheet", DefaultOMROptions(), 1, true, "[true true true false false true]"},
+		{"sequence 6", DefaultOMROptions(), 6, false, "[true false false true true true]"},
+		{"sequence wraps at 8", DefaultOMROptions(), 8, true, "[true true false false false false]"},
+		{"no sequence or parity", OMROptions{Enabled: true}, 5, true, "[true true]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marks := omrMarks(tt.omr, tt.sequence, tt.lastSheet)
+			if got := fmt.Sprint(marks); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if tt.omr.Parity {
+				count := 0
+				for _, on := range marks {
+					if on {
+						count++
+					}
+				}
+				if count%2 != 0 {
+					t.Errorf("%d marks, want an even number", count)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintBatchSheets(t *testing.T) {
+	dir := t.TempDir()
+	workbook := filepath.Join(dir, "batch.xlsx")
+	data := excel.DefaultOptions()
+	data.Rows = 60
+	data.Seed = 1
+	if err := excel.Generate(workbook, data); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	simplex, err := GeneratePrintBatch(workbook, filepath.Join(dir, "simplex"), Options{},
+		BatchOptions{LettersPerFile: 4, OMR: DefaultOMROptions()})
+	if err != nil {
+		t.Fatalf("GeneratePrintBatch simplex: %v", err)
+	}
+	duplex, err := GeneratePrintBatch(workbook, filepath.Join(dir, "duplex"), Options{},
+		BatchOptions{LettersPerFile: 4, Duplex: true, OMR: DefaultOMROptions()})
+	if err != nil {
+		t.Fatalf("GeneratePrintBatch duplex: %v", err)
+	}
+
+	if simplex.Letters == 0 || duplex.Letters != simplex.Letters || duplex.Envelopes != duplex.Letters {
+		t.Fatalf("letters %d simplex, %d duplex in %d envelopes", simplex.Letters, duplex.Letters, duplex.Envelopes)
+	}
+	if want := (simplex.Letters + 3) / 4; len(simplex.Files) != want || len(duplex.Files) != want {
+		t.Errorf("%d and %d files, want %d of 4 letters", len(simplex.Files), len(duplex.Files), want)
+	}
+	if simplex.Sheets != simplex.Pages {
+		t.Errorf("simplex: %d sheets for %d pages", simplex.Sheets, simplex.Pages)
+	}
+	blank := 0
+	for i, f := range duplex.Files {
+		blank += f.BlankPages
+		// Every letter is padded to whole sheets
+		if f.Pages%2 != 0 || f.Sheets*2 != f.Pages || f.BlankPages > f.Letters {
+			t.Errorf("duplex file %d: %d pages, %d blank, %d sheets for %d letters", i+1, f.Pages, f.BlankPages, f.Sheets, f.Letters)
+		}
+		if s := simplex.Files[i]; f.Pages != s.Pages+f.BlankPages || s.BlankPages != 0 {
+			t.Errorf("file %d: %d duplex pages with %d blank, %d simplex pages", i+1, f.Pages, f.BlankPages, s.Pages)
+		}
+	}
+	if duplex.Pages != simplex.Pages+blank || duplex.Sheets*2 != duplex.Pages {
+		t.Errorf("duplex: %d pages with %d blank and %d sheets, simplex %d pages", duplex.Pages, blank, duplex.Sheets, simplex.Pages)
+	}
+}
@@ -0,0 +1,153 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"dsb-excel-generator/pkg/equity"
+	"dsb-excel-generator/pkg/excel"
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// EquityReportFileName names the pay-equity report, without the extension.
+// {campaign} and {year} are replaced as in Options.FileNamePattern.
+const EquityReportFileName = "{campaign} – Ligelønsanalyse"
+
+// Danish headings of the analysis dimensions
+var equityDimensionNames = map[string]string{
+	equity.All:        "Alle medarbejdere",
+	equity.Department: "Afdeling",
+	equity.Agreement:  "Overenskomst",
+	equity.AgeBand:    "Aldersgruppe",
+}
+
+// equityColumns are the columns of the group tables; together they span the
+// text width of a landscape page
+var equityColumns = []tableColumn{
+	{"Gruppe", 44, "L"},
+	{"Kvinder", 16, "R"},
+	{"Mænd", 16, "R"},
+	{"Median K", 22, "R"},
+	{"Median M", 22, "R"},
+	{"Gab median", 20, "R"},
+	{"Gns. K", 22, "R"},
+	{"Gns. M", 22, "R"},
+	{"Gab gns.", 18, "R"},
+	{"Gab før", 17, "R"},
+	{"Stign. K", 19, "R"},
+	{"Stign. M", 19, "R"},
+}
+
+// GenerateEquityReport analyses pay equity between women and men in the
+// workbook and writes the analysis as an accessible PDF and a workbook with
+// the same name. The filter and the include and exclude lists of opts select
+// the employees analysed; ages are calculated on the payday.
+func GenerateEquityReport(excelFile string, outputDir string, opts Options) error {
+	opts = opts.withDefaults()
+
+	employees, err := excel.ReadEmployees(excelFile)
+	if err != nil {
+		return err
+	}
+	selected, err := selectEmployees(employees, opts)
+	if err != nil {
+		return err
+	}
+	report := equity.Analyze(selected, opts.Campaign.PayoutDate())
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+	base := filepath.Join(outputDir, LetterFileName(EquityReportFileName, opts.Campaign, models.EmployeeData{}))
+	if err := writeEquityReportPDF(report, base+".pdf", opts); err != nil {
+		return err
+	}
+	if err := excel.WriteEquityReport(base+".xlsx", report); err != nil {
+		return err
+	}
+
+	fmt.Printf("Adjusted pay gap %.2f%% (%.2f%% before the regulation), comparing %d of %d employees\n",
+		report.AdjustedGap, report.AdjustedGapBefore, report.Covered, report.Employees)
+	fmt.Printf("Wrote %s.pdf and %s.xlsx\n", base, base)
+	return nil
+}
+
+// writeEquityReportPDF writes the analysis: an explanation, the adjusted gap
+// and a table per dimension, on landscape pages
+func writeEquityReportPDF(report equity.Report, outputPath string, opts Options) error {
+	title := "Ligelønsanalyse – " + opts.Campaign.Title
+	pdf, tr := newDocument("L", opts.Campaign, title)
+	pdf.AddPage()
+
+	writeTitle(pdf, tr, title)
+	writeParagraph(pdf, tr, fmt.Sprintf("Analysen sammenligner kvinders (K) og mænds (M) løn, før %s godkendes. "+
+		"Køn og alder er udledt af CPR-nummeret, og alderen er opgjort %s. "+
+		"Lønnen er basislønnen efter reguleringen omregnet til fuld tid, i kr. pr. måned. "+
+		"Lønforskellen (gab) er mændenes løn minus kvindernes i procent af mændenes; et positivt gab betyder, at kvinder tjener mindre. "+
+		"Stigningen er den gennemsnitlige samlede stigning i procent. "+
+		"Tal for færre end %d kvinder eller mænd vises ikke (–).",
+		opts.Campaign.RegulationName(), report.On.Danish(), equity.MinGroupSize), 5)
+
+	writeHeading(pdf, tr, "Justeret lønforskel")
+	writeParagraph(pdf, tr, fmt.Sprintf("Sammenlignet inden for samme afdeling, overenskomst, aldersgruppe og rolle er lønforskellen "+
+		"%.2f%% efter reguleringen mod %.2f%% før. Sammenligningen omfatter %d af %d medarbejdere.",
+		report.AdjustedGap, report.AdjustedGapBefore, report.Covered, report.Employees), 3)
+	if len(report.Skipped) > 0 {
+		writeParagraph(pdf, tr, fmt.Sprintf("%d medarbejdere uden gyldigt CPR-nummer indgår ikke.", len(report.Skipped)), 3)
+	}
+	pdf.Ln(3)
+
+	for _, dimension := range equity.Dimensions {
+		writeHeading(pdf, tr, equityDimensionNames[dimension])
+		writeEquityTable(pdf, tr, report, dimension)
+	}
+
+	writeSignature(pdf, tr)
+
+	if err := pdf.OutputFileAndClose(outputPath); err != nil {
+		return fmt.Errorf("failed to write PDF: %v", err)
+	}
+	return nil
+}
+
+// writeEquityTable writes the groups of a dimension as a table
+func writeEquityTable(pdf *fpdf.Fpdf, tr func(string) string, report equity.Report, dimension string) {
+	var rows [][]string
+	for _, g := range report.Groups {
+		if g.Dimension != dimension {
+			continue
+		}
+		name := g.Name
+		if dimension == equity.All {
+			name = equityDimensionNames[equity.All]
+		}
+		women, men := g.Women.Reported(), g.Men.Reported()
+		rows = append(rows, []string{
+			name,
+			fmt.Sprintf("%d", g.Women.Count),
+			fmt.Sprintf("%d", g.Men.Count),
+			equityCell(women, "%.2f", g.Women.MedianNewSalary),
+			equityCell(men, "%.2f", g.Men.MedianNewSalary),
+			equityCell(women && men, "%.2f%%", g.MedianGap()),
+			equityCell(women, "%.2f", g.Women.MeanNewSalary),
+			equityCell(men, "%.2f", g.Men.MeanNewSalary),
+			equityCell(women && men, "%.2f%%", g.MeanGap()),
+			equityCell(women && men, "%.2f%%", g.MeanGapBefore()),
+			equityCell(women, "%.2f%%", g.Women.MeanIncrease),
+			equityCell(men, "%.2f%%", g.Men.MeanIncrease),
+		})
+	}
+	writeTable(pdf, tr, equityColumns, rows, nil)
+	pdf.Ln(6)
+}
+
+// equityCell formats a statistic, or a dash if it covers too few employees
+func equityCell(reported bool, format string, value float64) string {
+	if !reported {
+		return "–"
+	}
+	return fmt.Sprintf(format, value)
+}
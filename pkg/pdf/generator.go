@@ -4,36 +4,148 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 
+	"dsb-excel-generator/pkg/excel"
+	"dsb-excel-generator/pkg/filter"
 	"dsb-excel-generator/pkg/models"
 
 	"github.com/go-pdf/fpdf"
-	"github.com/xuri/excelize/v2"
 )
 
+// Options controls how the PDF letters are generated
+type Options struct {
+	// Limit caps the number of letters generated; 0 generates all rows
+	Limit int
+	// Sample, if set, generates a representative QA sample of this many letters
+	// instead of the first rows (see SampleEmployees)
+	Sample int
+	// Filter selects rows with an expression over header names,
+	// e.g. `Department == "IT" && PercentageIncrease > 3` (see package filter)
+	Filter string
+	// IncludeFile and ExcludeFile name list files of employee numbers or CPR
+	// numbers to include or exclude
+	IncludeFile string
+	ExcludeFile string
+	// AddressWindow places the recipient address at the window position
+	// of C5/DL envelopes on every letter. Without it, only letters sent by
+	// physical mail get the address block.
+	AddressWindow bool
+	// ReviewFile, if set, writes all generated letters into one combined PDF
+	// with a bookmark per employee, grouped by department and letter type
+	ReviewFile string
+	// SkipIndividual generates only the review file, not the individual letters
+	SkipIndividual bool
+	// Campaign supplies the title, year, agreement and payout texts
+	Campaign models.Campaign
+	// FileNamePattern names the individual letters. {campaign}, {year} and
+	// header names in braces, e.g. {FirstName}, are replaced with their values.
+	FileNamePattern string
+	// Workers is the number of letters rendered concurrently
+	Workers int
+	// Security controls password protection of individual letters
+	Security SecurityOptions
+	// Preflight, if set, runs the pre-flight check over the workbook and
+	// refuses to generate letters if it flags any row
+	Preflight *excel.PreflightRules
+}
+
+// Defaults for options left empty
+const (
+	DefaultFileNamePattern = "{campaign} – {FirstName} {LastName} – {CPR}.pdf"
+	DefaultWorkers         = 8
+)
+
+// withDefaults fills in the options left empty
+func (o Options) withDefaults() Options {
+	if o.Campaign.Title == "" {
+		o.Campaign = models.DefaultCampaign()
+	}
+	if o.FileNamePattern == "" {
+		o.FileNamePattern = DefaultFileNamePattern
+	}
+	if o.Workers <= 0 {
+		o.Workers = DefaultWorkers
+	}
+	return o
+}
+
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z]+)\}`)
+
+// LetterFileName builds the file name of a letter from the pattern
+func LetterFileName(pattern string, campaign models.Campaign, emp models.EmployeeData) string {
+	name := placeholderPattern.ReplaceAllStringFunc(pattern, func(placeholder string) string {
+		key := placeholder[1 : len(placeholder)-1]
+		switch key {
+		case "campaign":
+			return campaign.Title
+		case "year":
+			return strconv.Itoa(campaign.Year)
+		}
+		value, _ := emp.Field(key)
+		return value
+	})
+	// Values must not create subdirectories
+	return strings.NewReplacer("/", "-", "\\", "-").Replace(name)
+}
+
+// Window envelope address field (DIN 5008 layout, used for C5/DL envelopes), in mm
+const (
+	windowLeft   = 20.0
+	windowTop    = 45.0
+	windowWidth  = 85.0
+	windowHeight = 45.0
+	// Body text starts below the window so it never shows through the envelope
+	windowBodyTop = windowTop + windowHeight + 10
+)
+
+// senderReturnLine is printed above the recipient inside the envelope window
+const senderReturnLine = "DSB · HR Services & Compensation · Telegade 2 · 2630 Taastrup"
+
 // GeneratePDFs reads the Excel file and generates PDFs concurrently
-func GeneratePDFs(excelFile string, outputDir string, limit int) error {
+func GeneratePDFs(excelFile string, outputDir string, opts Options) error {
+	opts = opts.withDefaults()
+
 	// Create output directory
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %v", err)
 	}
 
-	// Open Excel file
-	f, err := excelize.OpenFile(excelFile)
+	employees, err := excel.ReadEmployees(excelFile)
 	if err != nil {
-		return fmt.Errorf("failed to open Excel file: %v", err)
+		return err
+	}
+	if err := checkPreflight(employees, opts); err != nil {
+		return err
 	}
-	defer f.Close()
 
-	// Get all rows
-	rows, err := f.GetRows("Sheet1")
+	// Select the rows to generate
+	selected, err := selectEmployees(employees, opts)
 	if err != nil {
-		return fmt.Errorf("failed to get rows: %v", err)
+		return err
+	}
+	if opts.Sample > 0 {
+		selected = SampleEmployees(selected, opts.Sample)
+	} else if opts.Limit > 0 && len(selected) > opts.Limit {
+		selected = selected[:opts.Limit]
+	}
+	fmt.Printf("Generating %d letters\n", len(selected))
+
+	if opts.ReviewFile != "" {
+		if err := writeReviewPDF(selected, opts.ReviewFile, opts); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote review file %s with %d letters\n", opts.ReviewFile, len(selected))
+	}
+	if opts.SkipIndividual {
+		return nil
 	}
 
 	var wg sync.WaitGroup
-	numWorkers := 8 // Can be adjusted based on CPU cores
+	numWorkers := opts.Workers
 	jobs := make(chan models.EmployeeData, 100)
 
 	// Start workers
@@ -42,10 +154,9 @@ func GeneratePDFs(excelFile string, outputDir string, limit int) error {
 		go func() {
 			defer wg.Done()
 			for emp := range jobs {
-				filename := fmt.Sprintf("Lønregulering 2025 – %s %s – %s.pdf",
-					emp.FirstName, emp.LastName, emp.CPR)
+				filename := LetterFileName(opts.FileNamePattern, opts.Campaign, emp)
 				filePath := filepath.Join(outputDir, filename)
-				if err := createWCAGCompliantPDF(emp, filePath); err != nil {
+				if err := createWCAGCompliantPDF(emp, filePath, opts); err != nil {
 					fmt.Printf("Error generating PDF for %s: %v\n", filename, err)
 				}
 			}
@@ -53,155 +164,182 @@ func GeneratePDFs(excelFile string, outputDir string, limit int) error {
 	}
 
 	// Send jobs
-	count := 0
-	for i, row := range rows {
-		if i == 0 {
-			continue // Skip header
+	for _, emp := range selected {
+		jobs <- emp
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	fmt.Printf("\nSuccessfully generated %d PDFs in %s\n", len(selected), outputDir)
+	return nil
+}
+
+// checkPreflight runs the pre-flight check if opts ask for it and fails if
+// it flags any row of the workbook
+func checkPreflight(employees []models.EmployeeData, opts Options) error {
+	if opts.Preflight == nil {
+		return nil
+	}
+	issues := excel.Preflight(employees, *opts.Preflight)
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("pre-flight check flagged %d issue(s); no letters were generated", len(issues))
+	}
+	return nil
+}
+
+// selectEmployees applies the filter expression and the include/exclude lists
+// and prints how many rows were selected
+func selectEmployees(employees []models.EmployeeData, opts Options) ([]models.EmployeeData, error) {
+	var expr *filter.Expr
+	if opts.Filter != "" {
+		var err error
+		if expr, err = filter.Parse(opts.Filter); err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %v", opts.Filter, err)
+		}
+		for _, name := range expr.Fields() {
+			if !excel.IsHeader(name) {
+				return nil, fmt.Errorf("invalid filter %q: unknown header %q", opts.Filter, name)
+			}
 		}
+	}
 
-		if limit > 0 && count >= limit {
-			break
+	var include, exclude filter.List
+	if opts.IncludeFile != "" {
+		var err error
+		if include, err = filter.LoadList(opts.IncludeFile); err != nil {
+			return nil, err
 		}
+	}
+	if opts.ExcludeFile != "" {
+		var err error
+		if exclude, err = filter.LoadList(opts.ExcludeFile); err != nil {
+			return nil, err
+		}
+	}
 
-		if len(row) < 11 {
+	var selected []models.EmployeeData
+	for _, emp := range employees {
+		if emp.CPR == "" {
 			continue
 		}
-
-		jobs <- models.EmployeeData{
-			CPR:                  row[0],
-			FirstName:            row[1],
-			LastName:             row[2],
-			BaseSalary:           row[3],
-			NewBaseSalary:        row[4],
-			GrossSalary:          row[5],
-			NewGrossSalary:       row[6],
-			IndividualAdjustment: row[7],
-			PercentageIncrease:   row[8],
-			EffectiveDate:        row[9],
-			PensionIncrease:      row[10],
+		if include != nil && !include.Contains(emp) {
+			continue
+		}
+		if exclude != nil && exclude.Contains(emp) {
+			continue
 		}
-		count++
+		if expr != nil {
+			match, err := expr.Match(emp)
+			if err != nil {
+				return nil, fmt.Errorf("filter %q on %s: %v", opts.Filter, emp.EmployeeNumber, err)
+			}
+			if !match {
+				continue
+			}
+		}
+		selected = append(selected, emp)
 	}
-	close(jobs)
 
-	wg.Wait()
+	fmt.Printf("Selected %d of %d rows\n", len(selected), len(employees))
+	return selected, nil
+}
+
+func createWCAGCompliantPDF(emp models.EmployeeData, outputPath string, opts Options) error {
+	pdf, tr := newLetterDocument(opts.Campaign, opts.Campaign.Title+" – "+emp.FullName())
+	applyProtection(pdf, emp, opts.Security)
+
+	pdf.AddPage()
+	writeLetter(pdf, tr, emp, opts)
+
+	// Write to file
+	err := pdf.OutputFileAndClose(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to write PDF: %v", err)
+	}
 
-	fmt.Printf("\nSuccessfully generated %d PDFs in %s\n", count, outputDir)
 	return nil
 }
 
-func createWCAGCompliantPDF(emp models.EmployeeData, outputPath string) error {
+// newLetterDocument creates a portrait A4 document with the shared
+// accessibility metadata and margins. It returns the document and its text
+// translator.
+func newLetterDocument(campaign models.Campaign, title string) (*fpdf.Fpdf, func(string) string) {
+	return newDocument("P", campaign, title)
+}
+
+// newDocument creates an A4 document in the orientation ("P" or "L") with the
+// shared accessibility metadata and margins
+func newDocument(orientation string, campaign models.Campaign, title string) (*fpdf.Fpdf, func(string) string) {
 	// Create new PDF with A4 page size
-	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf := fpdf.New(orientation, "mm", "A4", "")
 
 	// Enable UTF-8 support for Danish characters (æ, ø, å)
 	tr := pdf.UnicodeTranslatorFromDescriptor("cp1252")
 
 	// Set document metadata for accessibility
-	pdf.SetTitle(tr("Lønregulering 2025 – "+emp.FirstName+" "+emp.LastName), false)
+	pdf.SetTitle(tr(title), false)
 	pdf.SetAuthor("HR Services & Compensation", false)
-	pdf.SetSubject(tr("Lønregulering 2025"), false)
+	pdf.SetSubject(tr(campaign.Title), false)
 	pdf.SetCreator("DSB Salary Regulation System", false)
-	pdf.SetKeywords(tr("lønregulering salary 2025"), false)
-
-	pdf.AddPage()
+	pdf.SetKeywords(tr(fmt.Sprintf("%s salary %d", strings.ToLower(campaign.Title), campaign.Year)), false)
 
 	// Set margins for better readability (20mm all sides)
 	pdf.SetMargins(20, 20, 20)
 	pdf.SetAutoPageBreak(true, 20)
 
-	// WCAG AAA compliant: Black text (0,0,0) on white background = 21:1 contrast ratio
-	textColor := func() {
-		pdf.SetTextColor(0, 0, 0)
-	}
-
-	// Document Title (H1 equivalent)
-	pdf.SetFont("Helvetica", "B", 18)
-	textColor()
-	pdf.CellFormat(0, 15, tr("Lønregulering 2025"), "", 1, "C", false, 0, "")
-	pdf.Ln(5)
-
-	// Greeting
-	pdf.SetFont("Helvetica", "", 12)
-	textColor()
-	greeting := fmt.Sprintf("Kære %s %s", emp.FirstName, emp.LastName)
-	pdf.MultiCell(0, 7, tr(greeting), "", "L", false)
-	pdf.Ln(3)
-
-	// Introduction
-	intro := "Lønreguleringen 2025 for HK medarbejdere er nu afsluttet, og i dette brev kan du læse om hvad det betyder for dig."
-	pdf.MultiCell(0, 7, tr(intro), "", "L", false)
-	pdf.Ln(5)
-
-	// Section Heading (H2 equivalent)
-	pdf.SetFont("Helvetica", "B", 14)
-	textColor()
-	pdf.MultiCell(0, 7, tr("Regulering i henhold til overenskomst"), "", "L", false)
-	pdf.Ln(2)
-
-	// Overenskomst text
-	pdf.SetFont("Helvetica", "", 12)
-	textColor()
-	overenskomstText := "Følgende regulering er fastlagt i overenskomsten med virkning 1. maj 2025:"
-	pdf.MultiCell(0, 7, tr(overenskomstText), "", "L", false)
-	pdf.Ln(2)
-
-	// Bullet point
-	bulletText := fmt.Sprintf("• Forhøjelse af pensionsbidrag med %s%%", emp.PensionIncrease)
-	pdf.SetX(pdf.GetX() + 10) // Indent bullet
-	pdf.MultiCell(0, 7, tr(bulletText), "", "L", false)
-	pdf.Ln(5)
-
-	// Individual adjustment heading (H2 equivalent)
-	pdf.SetFont("Helvetica", "B", 14)
-	textColor()
-	pdf.MultiCell(0, 7, tr("Individuel lønregulering"), "", "L", false)
-	pdf.Ln(2)
-
-	// Individual adjustment text
-	pdf.SetFont("Helvetica", "", 12)
-	textColor()
-	individualText := fmt.Sprintf("Din nærmeste leder har besluttet, at du ud over den nævnte stigning i overenskomsten også skal have en individuel lønregulering gældende pr. %s.", emp.EffectiveDate)
-	pdf.MultiCell(0, 7, tr(individualText), "", "L", false)
-	pdf.Ln(5)
-
-	// Salary details with mixed formatting
-	pdf.SetFont("Helvetica", "", 12)
-	pdf.Write(7, tr("Din basisløn er blevet reguleret til "))
-	pdf.SetFont("Helvetica", "B", 12)
-	pdf.Write(7, tr(fmt.Sprintf("%s kr.", emp.NewBaseSalary)))
-	pdf.SetFont("Helvetica", "", 12)
-	pdf.Write(7, tr(" og din nye bruttoløn udgør nu "))
-	pdf.SetFont("Helvetica", "B", 12)
-	pdf.Write(7, tr(fmt.Sprintf("%s kr.", emp.NewGrossSalary)))
-	pdf.SetFont("Helvetica", "", 12)
-	pdf.Write(7, tr(fmt.Sprintf(" Den individuelle lønregulering på din bruttoløn er %s kr., svarende til en stigning på %s%%.", emp.IndividualAdjustment, emp.PercentageIncrease)))
-	pdf.Ln(10)
-
-	// Effective date
-	effectiveText := fmt.Sprintf("Din nye løn er med tilbagevirkende kraft fra den %s.", emp.EffectiveDate)
-	pdf.MultiCell(0, 7, tr(effectiveText), "", "L", false)
-	pdf.Ln(3)
-
-	// Payment timing
-	paymentText := "Denne individuelle regulering vil finde sted ved lønudbetalingen ultimo juni måned 2025."
-	pdf.MultiCell(0, 7, tr(paymentText), "", "L", false)
-	pdf.Ln(10)
-
-	// Signature
-	pdf.SetFont("Helvetica", "", 12)
-	textColor()
-	pdf.MultiCell(0, 7, tr("Med venlig hilsen"), "", "L", false)
-	pdf.Ln(1)
-
-	pdf.SetFont("Helvetica", "B", 12)
-	pdf.MultiCell(0, 7, "HR Services & Compensation", "", "L", false)
+	return pdf, tr
+}
 
-	// Write to file
-	err := pdf.OutputFileAndClose(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to write PDF: %v", err)
+// writeLetter writes a single employee letter starting on the current page.
+// Callers add the first page so they can bookmark or mark it.
+func writeLetter(pdf *fpdf.Fpdf, tr func(string) string, emp models.EmployeeData, opts Options) {
+	// Recipient address for window envelopes
+	if hasAddressWindow(emp, opts) {
+		writeAddressBlock(pdf, tr, emp)
+		pdf.SetY(windowBodyTop)
 	}
 
-	return nil
+	// Contract amendments and annual reviews use the salary regulation layout
+	switch emp.LetterType {
+	case "Pension Change":
+		writePensionChangeLetter(pdf, tr, emp, opts)
+	default:
+		writeSalaryRegulationLetter(pdf, tr, emp, opts)
+	}
+
+	writeSignature(pdf, tr)
+}
+
+// hasAddressWindow reports whether the letter gets the recipient address in
+// the envelope window: letters sent by physical mail do, others only with
+// opts.AddressWindow, and never without a complete address
+func hasAddressWindow(emp models.EmployeeData, opts Options) bool {
+	return (opts.AddressWindow || emp.DeliveryMethod == models.DeliveryPhysical) && emp.HasAddress()
+}
+
+// writeAddressBlock prints the sender return line and the recipient address
+// inside the envelope window area
+func writeAddressBlock(pdf *fpdf.Fpdf, tr func(string) string, emp models.EmployeeData) {
+	// Small sender line at the top of the window, as used by the postal service
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetXY(windowLeft, windowTop)
+	pdf.CellFormat(windowWidth, 5, tr(senderReturnLine), "B", 1, "L", false, 0, "")
+
+	// Recipient lines: name, street and house number, postcode and city
+	lines := []string{
+		emp.FullName(),
+		emp.Street + " " + emp.HouseNumber,
+		emp.PostCode + " " + emp.City,
+	}
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.SetXY(windowLeft, windowTop+8)
+	for _, line := range lines {
+		pdf.SetX(windowLeft)
+		pdf.CellFormat(windowWidth, 5.5, tr(line), "", 1, "L", false, 0, "")
+	}
 }
@@ -0,0 +1,35 @@
+package pdf
+
+import (
+	"testing"
+
+	"dsb-excel-generator/pkg/models"
+)
+
+func TestHasAddressWindow(t *testing.T) {
+	address := models.EmployeeData{Street: "Vesterbrogade", HouseNumber: "12", PostCode: "1620", City: "København V"}
+	withDelivery := func(method string) models.EmployeeData {
+		emp := address
+		emp.DeliveryMethod = method
+		return emp
+	}
+	tests := []struct {
+		name          string
+		emp           models.EmployeeData
+		addressWindow bool
+		want          bool
+	}{
+		{"physical mail", withDelivery(models.DeliveryPhysical), false, true},
+		{"digital post", withDelivery(models.DeliveryDigital), false, false},
+		{"digital post with override", withDelivery(models.DeliveryDigital), true, true},
+		{"physical mail without address", models.EmployeeData{DeliveryMethod: models.DeliveryPhysical}, false, false},
+		{"override without address", models.EmployeeData{DeliveryMethod: models.DeliveryDigital}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasAddressWindow(tt.emp, Options{AddressWindow: tt.addressWindow}); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package pdf
+
+import (
+	"fmt"
+
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// setTextColor sets black text: on the white page this gives a 21:1
+// contrast ratio, above the WCAG AAA requirement of 7:1
+func setTextColor(pdf *fpdf.Fpdf) {
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// writeTitle writes the letter title (H1 equivalent)
+func writeTitle(pdf *fpdf.Fpdf, tr func(string) string, title string) {
+	pdf.SetFont("Helvetica", "B", 18)
+	setTextColor(pdf)
+	pdf.CellFormat(0, 15, tr(title), "", 1, "C", false, 0, "")
+	pdf.Ln(5)
+}
+
+// writeHeading writes a section heading (H2 equivalent)
+func writeHeading(pdf *fpdf.Fpdf, tr func(string) string, heading string) {
+	pdf.SetFont("Helvetica", "B", 14)
+	setTextColor(pdf)
+	pdf.MultiCell(0, 7, tr(heading), "", "L", false)
+	pdf.Ln(2)
+}
+
+// writeParagraph writes body text followed by space
+func writeParagraph(pdf *fpdf.Fpdf, tr func(string) string, text string, space float64) {
+	pdf.SetFont("Helvetica", "", 12)
+	setTextColor(pdf)
+	pdf.MultiCell(0, 7, tr(text), "", "L", false)
+	pdf.Ln(space)
+}
+
+// writeSignature writes the closing and the sender department
+func writeSignature(pdf *fpdf.Fpdf, tr func(string) string) {
+	pdf.SetFont("Helvetica", "", 12)
+	setTextColor(pdf)
+	pdf.MultiCell(0, 7, tr("Med venlig hilsen"), "", "L", false)
+	pdf.Ln(1)
+
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.MultiCell(0, 7, "HR Services & Compensation", "", "L", false)
+}
+
+// employeeAgreement returns the employee's agreement. The pension increase
+// of the employee's row takes precedence over the agreement's.
+func employeeAgreement(emp models.EmployeeData, campaign models.Campaign) models.Agreement {
+	agreement := campaign.AgreementFor(emp.Agreement)
+	if emp.PensionIncrease != "" {
+		agreement.PensionIncrease = parseAmount(emp.PensionIncrease)
+	}
+	return agreement
+}
+
+// writeSalaryRegulationLetter writes the body of a salary regulation letter
+func writeSalaryRegulationLetter(pdf *fpdf.Fpdf, tr func(string) string, emp models.EmployeeData, opts Options) {
+	agreement := employeeAgreement(emp, opts.Campaign)
+	writeTitle(pdf, tr, opts.Campaign.Title)
+
+	writeParagraph(pdf, tr, fmt.Sprintf("Kære %s %s", emp.FirstName, emp.LastName), 3)
+	writeParagraph(pdf, tr, fmt.Sprintf("%s for %s er nu afsluttet, og i dette brev kan du læse om hvad det betyder for dig.",
+		opts.Campaign.RegulationName(), agreement.EmployeeGroup()), 5)
+
+	writeHeading(pdf, tr, "Regulering i henhold til overenskomst")
+	writeParagraph(pdf, tr, fmt.Sprintf("Følgende regulering er fastlagt i overenskomsten »%s« med virkning %s:",
+		agreement.Name, agreement.EffectiveDate.Danish()), 2)
+	for _, line := range agreement.Regulations() {
+		pdf.SetX(pdf.GetX() + 10) // Indent bullet
+		writeParagraph(pdf, tr, "• "+line, 0)
+	}
+	pdf.Ln(3)
+	writePensionTable(pdf, tr, emp.Pension())
+
+	writeHeading(pdf, tr, "Individuel lønregulering")
+	writeParagraph(pdf, tr, fmt.Sprintf("Din nærmeste leder har besluttet, at du ud over den nævnte stigning i overenskomsten også skal have en individuel lønregulering gældende pr. %s.",
+		emp.EffectiveDate.Danish()), 5)
+
+	// Salary details with mixed formatting
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.Write(7, tr("Din basisløn er blevet reguleret til "))
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.Write(7, tr(fmt.Sprintf("%s kr.", emp.NewBaseSalary)))
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.Write(7, tr(" og din nye bruttoløn udgør nu "))
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.Write(7, tr(fmt.Sprintf("%s kr.", emp.NewGrossSalary)))
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.Write(7, tr(fmt.Sprintf(" Den individuelle lønregulering på din bruttoløn er %s kr., svarende til en stigning på %s%%.", emp.IndividualAdjustment, emp.PercentageIncrease)))
+	pdf.Ln(10)
+
+	if placement := emp.Placement(); placement != "" {
+		writeParagraph(pdf, tr, placement, 3)
+	}
+	if hoursText := emp.HoursText(); hoursText != "" {
+		writeParagraph(pdf, tr, hoursText, 3)
+	}
+
+	writeParagraph(pdf, tr, fmt.Sprintf("Din nye løn er med tilbagevirkende kraft fra den %s.", emp.EffectiveDate.Danish()), 3)
+	if backPayText := opts.Campaign.BackPayText(emp.EffectiveDate, parseAmount(emp.BackPay)); backPayText != "" {
+		writeParagraph(pdf, tr, backPayText, 3)
+	}
+	writeParagraph(pdf, tr, fmt.Sprintf("Denne individuelle regulering vil finde sted ved lønudbetalingen %s.", opts.Campaign.PayoutText()), 10)
+}
+
+// writePensionChangeLetter writes the body of a pension change letter
+func writePensionChangeLetter(pdf *fpdf.Fpdf, tr func(string) string, emp models.EmployeeData, opts Options) {
+	writeTitle(pdf, tr, "Ændring af pensionsbidrag")
+
+	writeParagraph(pdf, tr, fmt.Sprintf("Kære %s %s", emp.FirstName, emp.LastName), 3)
+	writeParagraph(pdf, tr, "Vi ønsker at informere dig om en ændring i dit pensionsbidrag.", 3)
+	writeParagraph(pdf, tr, fmt.Sprintf("Med virkning fra %s vil dit pensionsbidrag blive forhøjet med %s%%.",
+		emp.EffectiveDate.Danish(), emp.PensionIncrease), 3)
+	writePensionTable(pdf, tr, emp.Pension())
+	writeParagraph(pdf, tr, fmt.Sprintf("Din nuværende bruttoløn på %s kr. forbliver uændret. Ændringen påvirker kun pensionsbidraget.", emp.GrossSalary), 3)
+	writeParagraph(pdf, tr, fmt.Sprintf("Ændringen er en del af den nye overenskomst »%s« og vil fremgå af din næste lønseddel.",
+		employeeAgreement(emp, opts.Campaign).Name), 10)
+}
+
+// Pension table column widths in mm; together they span the text width
+var pensionColumnWidths = []float64{50, 30, 30, 30, 30}
+
+// writePensionTable writes the monthly pension contributions before and
+// after the regulation as a table with a header row
+func writePensionTable(pdf *fpdf.Fpdf, tr func(string) string, pension models.Pension) {
+	if pension.Rate() == 0 {
+		return // Workbooks without pension columns
+	}
+
+	setTextColor(pdf)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetLineWidth(0.2)
+
+	pdf.SetFont("Helvetica", "B", 11)
+	for i, header := range []string{"Pension pr. måned", "Sats før", "Bidrag før", "Sats efter", "Bidrag efter"} {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(pensionColumnWidths[i], 8, tr(header), "1", 0, align, false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	shares := pension.Shares()
+	for i, share := range shares {
+		// The total line is bold
+		style := ""
+		if i == len(shares)-1 {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 11)
+		pdf.CellFormat(pensionColumnWidths[0], 8, tr(share.Name), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(pensionColumnWidths[1], 8, fmt.Sprintf("%.2f%%", share.Rate), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(pensionColumnWidths[2], 8, fmt.Sprintf("%.2f kr.", share.Contribution), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(pensionColumnWidths[3], 8, fmt.Sprintf("%.2f%%", share.NewRate), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(pensionColumnWidths[4], 8, fmt.Sprintf("%.2f kr.", share.NewContribution), "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+	}
+	pdf.Ln(5)
+}
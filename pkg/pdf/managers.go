@@ -0,0 +1,138 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"dsb-excel-generator/pkg/excel"
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// ManagerReportFileName names the manager reports, without the extension.
+// Placeholders are replaced as in Options.FileNamePattern with the
+// manager's values.
+const ManagerReportFileName = "{campaign} – Lederrapport – {FirstName} {LastName} – {EmployeeNumber}"
+
+// managerReportColumns are the columns of the team table; together they
+// span the text width of a landscape page
+var managerReportColumns = []tableColumn{
+	{"Medarbejder", 50, "L"},
+	{"Medarb.nr.", 24, "L"},
+	{"Nærmeste leder", 45, "L"},
+	{"Brevtype", 42, "L"},
+	{"Basisløn før", 30, "R"},
+	{"Basisløn efter", 30, "R"},
+	{"Individuel", 18, "R"},
+	{"I alt", 18, "R"},
+}
+
+// GenerateManagerReports writes a report for every department head and team
+// lead listing the regulation of everyone below them in the organisation,
+// as an accessible PDF and a workbook with the same name. The filter and the
+// include and exclude lists of opts limit the employees listed; managers
+// without any selected employees get no report.
+func GenerateManagerReports(excelFile string, outputDir string, opts Options) error {
+	opts = opts.withDefaults()
+
+	employees, err := excel.ReadEmployees(excelFile)
+	if err != nil {
+		return err
+	}
+	selected, err := selectEmployees(employees, opts)
+	if err != nil {
+		return err
+	}
+	included := make(map[string]bool, len(selected))
+	for _, emp := range selected {
+		included[emp.EmployeeNumber] = true
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	tree := models.NewOrgTree(employees)
+	reports := 0
+	for _, manager := range employees {
+		if !manager.IsManager() {
+			continue
+		}
+		var team []models.EmployeeData
+		for _, emp := range tree.Team(manager.EmployeeNumber) {
+			if included[emp.EmployeeNumber] {
+				team = append(team, emp)
+			}
+		}
+		if len(team) == 0 {
+			continue
+		}
+
+		base := filepath.Join(outputDir, LetterFileName(ManagerReportFileName, opts.Campaign, manager))
+		if err := writeManagerReportPDF(manager, team, base+".pdf", opts); err != nil {
+			return fmt.Errorf("manager report for %s: %v", manager.EmployeeNumber, err)
+		}
+		if err := excel.WriteManagerReport(base+".xlsx", manager, team); err != nil {
+			return fmt.Errorf("manager report for %s: %v", manager.EmployeeNumber, err)
+		}
+		reports++
+	}
+
+	fmt.Printf("Wrote reports for %d managers in %s\n", reports, outputDir)
+	return nil
+}
+
+// writeManagerReportPDF writes a manager's report: an introduction, the
+// team table with totals and the signature, on landscape pages
+func writeManagerReportPDF(manager models.EmployeeData, team []models.EmployeeData, outputPath string, opts Options) error {
+	title := "Lederrapport – " + opts.Campaign.Title
+	pdf, tr := newDocument("L", opts.Campaign, title+" – "+manager.FullName())
+	pdf.AddPage()
+
+	writeTitle(pdf, tr, title)
+	writeParagraph(pdf, tr, fmt.Sprintf("Kære %s", manager.FullName()), 3)
+	writeParagraph(pdf, tr, fmt.Sprintf("Her er en oversigt over %s for de %d medarbejdere i dit team i %s, inden brevene sendes ud. "+
+		"Basislønnen er pr. måned. Den individuelle regulering og den samlede stigning er i procent af basislønnen før reguleringen. "+
+		"Kontakt HR Services & Compensation, hvis noget skal rettes.",
+		opts.Campaign.RegulationName(), len(team), manager.Department), 5)
+
+	writeHeading(pdf, tr, "Medarbejdere")
+	writeManagerReportTable(pdf, tr, team)
+
+	writeSignature(pdf, tr)
+
+	if err := pdf.OutputFileAndClose(outputPath); err != nil {
+		return fmt.Errorf("failed to write PDF: %v", err)
+	}
+	return nil
+}
+
+// writeManagerReportTable writes the team as a table with a bold totals row
+func writeManagerReportTable(pdf *fpdf.Fpdf, tr func(string) string, team []models.EmployeeData) {
+	var rows [][]string
+	for _, emp := range team {
+		rows = append(rows, []string{
+			emp.FullName(),
+			emp.EmployeeNumber,
+			emp.ManagerName,
+			emp.LetterType,
+			emp.BaseSalary + " kr.",
+			emp.NewBaseSalary + " kr.",
+			emp.PercentageIncrease + "%",
+			fmt.Sprintf("%.2f%%", emp.TotalIncrease()),
+		})
+	}
+
+	totals := models.Totals(team)
+	writeTable(pdf, tr, managerReportColumns, rows, []string{
+		fmt.Sprintf("I alt (%d medarbejdere)", totals.Employees),
+		"", "", "",
+		fmt.Sprintf("%.2f kr.", totals.BaseSalary),
+		fmt.Sprintf("%.2f kr.", totals.NewBaseSalary),
+		fmt.Sprintf("%.2f%%", totals.IndividualPercentage()),
+		fmt.Sprintf("%.2f%%", totals.TotalIncrease()),
+	})
+	pdf.Ln(8)
+}
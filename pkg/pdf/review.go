@@ -0,0 +1,57 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"dsb-excel-generator/pkg/models"
+)
+
+// writeReviewPDF renders all letters into one combined PDF for proofreading.
+// Letters are grouped by department and letter type, and the document outline
+// has a bookmark for every group and every employee.
+func writeReviewPDF(employees []models.EmployeeData, outputPath string, opts Options) error {
+	letters := make([]models.EmployeeData, len(employees))
+	copy(letters, employees)
+	sort.SliceStable(letters, func(i, j int) bool {
+		a, b := letters[i], letters[j]
+		if a.Department != b.Department {
+			return a.Department < b.Department
+		}
+		if a.LetterType != b.LetterType {
+			return a.LetterType < b.LetterType
+		}
+		return a.EmployeeNumber < b.EmployeeNumber
+	})
+
+	pdf, tr := newLetterDocument(opts.Campaign, "Gennemsyn – "+opts.Campaign.Title)
+
+	department, letterType := "", ""
+	for i, emp := range letters {
+		pdf.AddPage()
+
+		// Outline: department > letter type > employee
+		if i == 0 || emp.Department != department {
+			department = emp.Department
+			letterType = ""
+			pdf.Bookmark(tr(department), 0, 0)
+		}
+		if emp.LetterType != letterType {
+			letterType = emp.LetterType
+			pdf.Bookmark(tr(letterType), 1, 0)
+		}
+		pdf.Bookmark(tr(fmt.Sprintf("%s (%s)", emp.FullName(), emp.EmployeeNumber)), 2, 0)
+
+		writeLetter(pdf, tr, emp, opts)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create review directory: %v", err)
+	}
+	if err := pdf.OutputFileAndClose(outputPath); err != nil {
+		return fmt.Errorf("failed to write review PDF: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package pdf
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"dsb-excel-generator/pkg/models"
+)
+
+// SampleEmployees picks n rows for QA review. The largest and smallest
+// increases and the longest names come first, then one row per LetterType ×
+// Department × SecurityLevel combination, then the other edge cases (one
+// row per distinct additional note and per employment type and letter
+// type). Remaining slots are filled with rows spread evenly over the data.
+// The selection is deterministic, so a rerun shows the same letters.
+func SampleEmployees(employees []models.EmployeeData, n int) []models.EmployeeData {
+	if n <= 0 || len(employees) == 0 {
+		return nil
+	}
+	if n >= len(employees) {
+		return employees
+	}
+
+	picked := make(map[int]bool)
+	var order []int
+	pick := func(i int) {
+		if i >= 0 && !picked[i] {
+			picked[i] = true
+			order = append(order, i)
+		}
+	}
+
+	// Extremes
+	pick(extremeIndex(employees, func(e models.EmployeeData) float64 { return parseAmount(e.PercentageIncrease) }))
+	pick(extremeIndex(employees, func(e models.EmployeeData) float64 { return -parseAmount(e.PercentageIncrease) }))
+	pick(extremeIndex(employees, func(e models.EmployeeData) float64 { return parseAmount(e.IndividualAdjustment) }))
+	pick(extremeIndex(employees, func(e models.EmployeeData) float64 { return -parseAmount(e.IndividualAdjustment) }))
+	pick(extremeIndex(employees, func(e models.EmployeeData) float64 { return float64(len([]rune(e.FullName()))) }))
+	pick(extremeIndex(employees, func(e models.EmployeeData) float64 { return float64(len([]rune(e.LastName))) }))
+
+	// One row per LetterType × Department × SecurityLevel combination,
+	// reusing the extremes for theirs
+	combos := make(map[string]int)
+	for _, i := range order {
+		combos[comboKey(employees[i])] = i
+	}
+	for i, emp := range employees {
+		if _, ok := combos[comboKey(emp)]; !ok {
+			combos[comboKey(emp)] = i
+		}
+	}
+	keys := make([]string, 0, len(combos))
+	for key := range combos {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		pick(combos[key])
+	}
+
+	// Other edge cases
+	seenNotes := make(map[string]bool)
+	for i, emp := range employees {
+		if emp.AdditionalNotes != "" && !seenNotes[emp.AdditionalNotes] {
+			seenNotes[emp.AdditionalNotes] = true
+			pick(i)
+		}
+	}
+	seenEmployment := make(map[string]bool)
+	for i, emp := range employees {
+		if key := emp.EmploymentType + "|" + emp.LetterType; !seenEmployment[key] {
+			seenEmployment[key] = true
+			pick(i)
+		}
+	}
+
+	if len(order) > n {
+		// Each combination missing from the sample has its own row among
+		// the ones left out
+		covered := make(map[string]bool)
+		for _, i := range order[:n] {
+			covered[comboKey(employees[i])] = true
+		}
+		missing := len(combos) - len(covered)
+		fmt.Printf("Warning: sample of %d letters leaves out %d of %d LetterType × Department × SecurityLevel combinations and %d other edge cases\n",
+			n, missing, len(combos), len(order)-n-missing)
+		order = order[:n]
+	}
+
+	// Fill the remaining slots with rows spread evenly over the data
+	for step := len(employees) / (n - len(order) + 1); len(order) < n; step = max(step/2, 1) {
+		for i := 0; i < len(employees) && len(order) < n; i += step {
+			pick(i)
+		}
+	}
+
+	sample := make([]models.EmployeeData, len(order))
+	for i, idx := range order {
+		sample[i] = employees[idx]
+	}
+	return sample
+}
+
+// comboKey identifies the row's LetterType × Department × SecurityLevel combination
+func comboKey(emp models.EmployeeData) string {
+	return emp.LetterType + "|" + emp.Department + "|" + emp.SecurityLevel
+}
+
+// extremeIndex returns the index of the row with the highest score, or -1
+func extremeIndex(employees []models.EmployeeData, score func(models.EmployeeData) float64) int {
+	best := -1
+	var bestScore float64
+	for i, emp := range employees {
+		if s := score(emp); best < 0 || s > bestScore {
+			best, bestScore = i, s
+		}
+	}
+	return best
+}
+
+// parseAmount parses a decimal amount from the workbook, returning 0 if invalid
+func parseAmount(s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
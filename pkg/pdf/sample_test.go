@@ -0,0 +1,103 @@
+package pdf
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"dsb-excel-generator/pkg/excel"
+	"dsb-excel-generator/pkg/models"
+)
+
+// sampleData returns rows cycling through every combination
+func sampleData(rows int) []models.EmployeeData {
+	employees := make([]models.EmployeeData, rows)
+	for i := range employees {
+		employees[i] = models.EmployeeData{
+			EmployeeNumber:       fmt.Sprintf("%05d", i+1),
+			FirstName:            "Anne",
+			LastName:             "Jensen",
+			LetterType:           excel.LetterTypes[i%4],
+			SecurityLevel:        excel.SecurityLevels[(i/4)%3],
+			Department:           fmt.Sprintf("Department %d", (i/12)%10),
+			EmploymentType:       models.EmploymentTypes[i%3],
+			PercentageIncrease:   fmt.Sprintf("%.2f", 0.5+float64(i%450)/100),
+			IndividualAdjustment: fmt.Sprintf("%.2f", float64((i*37)%2000)),
+		}
+		// Notes on rows of a single combination, so they are edge cases of
+		// their own
+		if i%120 == 0 {
+			employees[i].AdditionalNotes = fmt.Sprintf("Note %d", i/120%20)
+		}
+	}
+	employees[rows/2].LastName = "Christoffersen-Vestergaard"
+	return employees
+}
+
+func combinations(employees []models.EmployeeData) map[string]bool {
+	keys := make(map[string]bool)
+	for _, emp := range employees {
+		keys[comboKey(emp)] = true
+	}
+	return keys
+}
+
+func TestSampleEmployeesDeterministic(t *testing.T) {
+	employees := sampleData(3000)
+	for _, n := range []int{1, 10, 50, 200} {
+		first := SampleEmployees(employees, n)
+		second := SampleEmployees(employees, n)
+		if len(first) != n {
+			t.Errorf("n=%d: got %d rows", n, len(first))
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("n=%d: samples differ between runs", n)
+		}
+		seen := make(map[string]bool)
+		for _, emp := range first {
+			if seen[emp.EmployeeNumber] {
+				t.Errorf("n=%d: employee %s picked twice", n, emp.EmployeeNumber)
+			}
+			seen[emp.EmployeeNumber] = true
+		}
+	}
+}
+
+func TestSampleEmployeesCoversCombinations(t *testing.T) {
+	employees := sampleData(3000)
+	all := combinations(employees)
+	if len(all) != 120 {
+		t.Fatalf("test data has %d combinations, want 120", len(all))
+	}
+
+	// The six extremes come first, then every combination
+	sample := SampleEmployees(employees, 6+len(all))
+	if got := combinations(sample); len(got) != len(all) {
+		t.Errorf("sample of %d covers %d of %d combinations", len(sample), len(got), len(all))
+	}
+
+	// Larger samples keep the combinations and add the other edge cases
+	sample = SampleEmployees(employees, 300)
+	if got := combinations(sample); len(got) != len(all) {
+		t.Errorf("sample of %d covers %d of %d combinations", len(sample), len(got), len(all))
+	}
+	notes := make(map[string]bool)
+	for _, emp := range sample {
+		if emp.AdditionalNotes != "" {
+			notes[emp.AdditionalNotes] = true
+		}
+	}
+	if len(notes) != 20 {
+		t.Errorf("sample of %d covers %d of 20 additional notes", len(sample), len(notes))
+	}
+}
+
+func TestSampleEmployeesSmallData(t *testing.T) {
+	employees := sampleData(20)
+	if got := SampleEmployees(employees, 20); len(got) != 20 {
+		t.Errorf("got %d rows, want all 20", len(got))
+	}
+	if got := SampleEmployees(employees, 0); got != nil {
+		t.Errorf("got %d rows for n=0", len(got))
+	}
+}
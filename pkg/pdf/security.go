@@ -0,0 +1,60 @@
+package pdf
+
+import (
+	"dsb-excel-generator/pkg/models"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// User password modes for protected letters
+const (
+	UserPasswordNone      = "none"
+	UserPasswordBirthdate = "birthdate"
+)
+
+// SecurityOptions controls password protection of individual letters
+type SecurityOptions struct {
+	// ProtectLevels lists the SecurityLevel values whose letters are protected
+	ProtectLevels []string
+	// OwnerPassword grants full access to protected letters.
+	// If empty, a random password is used and full access is not possible.
+	OwnerPassword string
+	// UserPassword is UserPasswordNone or UserPasswordBirthdate, which
+	// requires the first six digits of the recipient's CPR number to open the letter
+	UserPassword string
+	// AllowPrint and AllowCopy set the permissions of protected letters
+	AllowPrint bool
+	AllowCopy  bool
+}
+
+// protects reports whether letters with the given security level are protected
+func (s SecurityOptions) protects(level string) bool {
+	for _, l := range s.ProtectLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// applyProtection encrypts the letter if its security level requires it
+func applyProtection(pdf *fpdf.Fpdf, emp models.EmployeeData, security SecurityOptions) {
+	if !security.protects(emp.SecurityLevel) {
+		return
+	}
+
+	var permissions byte
+	if security.AllowPrint {
+		permissions |= fpdf.CnProtectPrint
+	}
+	if security.AllowCopy {
+		permissions |= fpdf.CnProtectCopy
+	}
+
+	userPassword := ""
+	if security.UserPassword == UserPasswordBirthdate && len(emp.CPR) >= 6 {
+		userPassword = emp.CPR[:6]
+	}
+
+	pdf.SetProtection(permissions, userPassword, security.OwnerPassword)
+}
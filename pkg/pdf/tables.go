@@ -0,0 +1,57 @@
+package pdf
+
+import "github.com/go-pdf/fpdf"
+
+// tableColumn is a column of a table: its header, width in mm and alignment
+// ("L" or "R")
+type tableColumn struct {
+	Header string
+	Width  float64
+	Align  string
+}
+
+// writeTable writes rows under a header row, followed by an optional bold
+// totals row. Rows never split across pages, and every page the table
+// continues on starts with the header row.
+func writeTable(pdf *fpdf.Fpdf, tr func(string) string, columns []tableColumn, rows [][]string, totals []string) {
+	setTextColor(pdf)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetLineWidth(0.2)
+
+	const headerHeight, rowHeight = 8.0, 7.0
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	fits := func(height float64) bool {
+		return pdf.GetY()+height <= pageHeight-bottom
+	}
+
+	header := func() {
+		pdf.SetFont("Helvetica", "B", 10)
+		for _, c := range columns {
+			pdf.CellFormat(c.Width, headerHeight, tr(c.Header), "1", 0, c.Align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	row := func(values []string, style string) {
+		if !fits(rowHeight) {
+			pdf.AddPage()
+			header()
+		}
+		pdf.SetFont("Helvetica", style, 10)
+		for i, c := range columns {
+			pdf.CellFormat(c.Width, rowHeight, tr(values[i]), "1", 0, c.Align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	if !fits(headerHeight + rowHeight) {
+		pdf.AddPage()
+	}
+	header()
+	for _, values := range rows {
+		row(values, "")
+	}
+	if totals != nil {
+		row(totals, "B")
+	}
+}